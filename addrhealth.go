@@ -0,0 +1,157 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 18:05 (EDT)
+// Function: track which of a peer's addresses actually work
+
+package kibitz
+
+import (
+	"time"
+)
+
+const ADDRFAIL = 3 // consecutive failures before we stop preferring an address
+
+type AddrHealth struct {
+	Addr       string
+	Natdom     string
+	NumOK      int
+	NumFail    int
+	ConsecFail int
+	LastOK     time.Time
+	LastFail   time.Time
+}
+
+// record the result of talking to a peer at an address
+func (pdb *DB) addrResult(id string, addr string, ok bool) {
+
+	pdb.lock.RLock()
+	p := pdb.find(id)
+	pdb.lock.RUnlock()
+
+	if p == nil {
+		return
+	}
+
+	p.addrResult(addr, ok)
+}
+
+func (p *Peer) addrResult(addr string, ok bool) {
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if !hasAddr(p.info, addr) {
+		return
+	}
+
+	h := p.addrs[addr]
+	if h == nil {
+		if p.addrs == nil {
+			p.addrs = make(map[string]*AddrHealth)
+		}
+		h = &AddrHealth{Addr: addr}
+		p.addrs[addr] = h
+	}
+
+	now := time.Now()
+
+	if ok {
+		h.NumOK++
+		h.ConsecFail = 0
+		h.LastOK = now
+		// stick with what works
+		p.sticky = addr
+	} else {
+		h.NumFail++
+		h.ConsecFail++
+		h.LastFail = now
+
+		if p.sticky == addr && h.ConsecFail >= ADDRFAIL {
+			dl.Debug("peer %s addr %s failing", p.id, addr)
+			p.sticky = ""
+		}
+	}
+
+	bestaddr := p.figureBestAddr(p.info)
+	if bestaddr != p.bestAddr {
+		p.bestAddr = bestaddr
+		p.changeStatus(p.status, true)
+	}
+}
+
+// the address that last worked, if it still exists
+func (p *Peer) preferredAddr() *NetInfo {
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.sticky == "" {
+		return nil
+	}
+
+	for _, ni := range p.info.GetNetInfo() {
+		if ni.GetAddr() == p.sticky {
+			return ni
+		}
+	}
+	return nil
+}
+
+func (p *Peer) addrFailing(addr string) bool {
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.failing(addr)
+}
+
+// caller must hold lock
+func (p *Peer) failing(addr string) bool {
+
+	h := p.addrs[addr]
+	return h != nil && h.ConsecFail >= ADDRFAIL
+}
+
+// forget about addresses the peer no longer has
+// caller must hold lock
+func (p *Peer) pruneAddrs() {
+
+	for addr := range p.addrs {
+		if !hasAddr(p.info, addr) {
+			delete(p.addrs, addr)
+		}
+	}
+
+	if p.sticky != "" && !hasAddr(p.info, p.sticky) {
+		p.sticky = ""
+	}
+}
+
+// caller must hold lock
+func (p *Peer) exportAddrs() []AddrHealth {
+
+	var res []AddrHealth
+
+	for _, ni := range p.info.GetNetInfo() {
+		h := AddrHealth{Addr: ni.GetAddr()}
+
+		if ph := p.addrs[ni.GetAddr()]; ph != nil {
+			h = *ph
+		}
+		h.Natdom = p.pdb.natdomOf(ni)
+
+		res = append(res, h)
+	}
+
+	return res
+}
+
+func hasAddr(pi *PeerInfo, addr string) bool {
+
+	for _, ni := range pi.GetNetInfo() {
+		if ni.GetAddr() == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 23:05 (EDT)
+// Function: http endpoint for operators
+
+package kibitz
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type adminStatus struct {
+	Bans       []Ban
+	Tombstones []*Tombstone
+}
+
+// AdminHTTP is an http handler for operators. mount it somewhere private.
+//
+//	GET                                     => current bans + tombstones
+//	POST op=leave&id=<server id>            => evict a peer
+//	POST op=ban&target=<id|ip|cidr>&for=1h  => ban
+//	POST op=unban&target=<id|ip|cidr>       => unban
+func (pdb *DB) AdminHTTP(w http.ResponseWriter, req *http.Request) {
+
+	switch req.Method {
+	case "GET":
+	case "POST":
+		if !pdb.adminOp(w, req) {
+			return
+		}
+	default:
+		w.WriteHeader(405)
+		return
+	}
+
+	js, _ := json.Marshal(&adminStatus{
+		Bans:       pdb.Bans(),
+		Tombstones: pdb.Tombstones(),
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Write(js)
+}
+
+func (pdb *DB) adminOp(w http.ResponseWriter, req *http.Request) bool {
+
+	switch req.FormValue("op") {
+	case "leave":
+		err := pdb.ForceLeave(req.FormValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), 404)
+			return false
+		}
+
+	case "ban":
+		target := req.FormValue("target")
+		if target == "" {
+			http.Error(w, "missing target", 400)
+			return false
+		}
+
+		var dur time.Duration
+		if f := req.FormValue("for"); f != "" {
+			var err error
+			dur, err = time.ParseDuration(f)
+			if err != nil {
+				http.Error(w, err.Error(), 400)
+				return false
+			}
+		}
+
+		pdb.Ban(target, dur)
+
+	case "unban":
+		pdb.Unban(req.FormValue("target"))
+
+	default:
+		http.Error(w, "invalid op", 400)
+		return false
+	}
+
+	return true
+}
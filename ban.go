@@ -0,0 +1,261 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 22:40 (EDT)
+// Function: operator controls - evict + ban peers
+
+package kibitz
+
+import (
+	"errors"
+	"expvar"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+const BANTIME = time.Hour
+
+var ErrUnknownPeer = errors.New("unknown peer")
+
+var evictions = expvar.NewInt("kibitz_evictions")
+var banrejects = expvar.NewInt("kibitz_ban_rejects")
+
+type Ban struct {
+	Target  string // server id or cidr
+	Expires time.Time
+}
+
+type banList struct {
+	lock sync.RWMutex
+	bans map[string]*banEntry
+}
+
+type banEntry struct {
+	net     *net.IPNet // nil for a server id
+	expires time.Time
+}
+
+func banListNew() *banList {
+	return &banList{
+		bans: make(map[string]*banEntry),
+	}
+}
+
+// ForceLeave removes a peer from the cluster.
+// an eviction record is spread to everyone, so they remove it too.
+// it is kept out for TombstoneTime, even if it is still running.
+func (pdb *DB) ForceLeave(id string) error {
+
+	pdb.lock.Lock()
+
+	p := pdb.find(id)
+	if p == nil {
+		pdb.lock.Unlock()
+		return ErrUnknownPeer
+	}
+
+	now := pdb.clock.Inc().Uint64()
+
+	p.lock.Lock()
+	pi := *p.info
+	p.lock.Unlock()
+
+	pi.SetStatusCode(STATUS_DEAD)
+	pi.EvictedBy = pdb.id
+	pi.TimeCreated = now
+	pi.TimeChecked = now
+	pi.Via = viaDot
+	pi.ViaPath = nil
+	pi.Hops = 0
+	pi.Broadcast = nil
+
+	pdb.evict(p, &pi)
+	pdb.lock.Unlock()
+
+	evictions.Add(1)
+
+	// tell everyone
+	pdb.rumor(id)
+	return nil
+}
+
+// replace the peer's record with the eviction record, and remove it
+// caller must hold lock
+func (pdb *DB) evict(p *Peer, pi *PeerInfo) {
+
+	dl.Verbose("peer %s evicted by %s", p.id, pi.GetEvictedBy())
+
+	p.lock.Lock()
+	p.info = pi
+	p.lock.Unlock()
+
+	pdb.kill(p, TOMB_EVICTED)
+}
+
+// Ban refuses to talk to, or hear about, matching peers.
+// target is a server id, an ip address, or a cidr.
+func (pdb *DB) Ban(target string, dur time.Duration) {
+
+	if dur <= 0 {
+		dur = BANTIME
+	}
+
+	pdb.bans.add(target, time.Now().Add(dur))
+	dl.Verbose("banned %s for %s", target, dur)
+
+	// get rid of them
+	pdb.lock.Lock()
+	defer pdb.lock.Unlock()
+
+	for _, peers := range []map[string]*Peer{pdb.allpeers, pdb.skeptical} {
+		for _, p := range peers {
+			if pdb.bans.isBanned(p.info) {
+				pdb.kill(p, TOMB_BANNED)
+			}
+		}
+	}
+}
+
+func (pdb *DB) Unban(target string) {
+	pdb.bans.remove(target)
+}
+
+// Bans returns the current bans
+func (pdb *DB) Bans() []Ban {
+	return pdb.bans.list()
+}
+
+// IsBanned is for transports, to refuse requests from banned peers
+func (pdb *DB) IsBanned(px PeerImport) bool {
+
+	if px == nil {
+		return false
+	}
+	return pdb.bans.isBanned(px.GetPeerInfo())
+}
+
+// ################################################################
+
+func (bl *banList) add(target string, expires time.Time) {
+
+	be := &banEntry{expires: expires}
+
+	if _, block, err := net.ParseCIDR(target); err == nil {
+		be.net = block
+		target = block.String()
+	} else if ip := net.ParseIP(target); ip != nil {
+		bits := 8 * len(ip.To4())
+		if bits == 0 {
+			bits = 128
+		}
+		be.net = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	}
+
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+
+	bl.bans[target] = be
+}
+
+func (bl *banList) remove(target string) {
+
+	if _, block, err := net.ParseCIDR(target); err == nil {
+		target = block.String()
+	}
+
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+
+	delete(bl.bans, target)
+}
+
+func (bl *banList) list() []Ban {
+
+	bl.lock.RLock()
+	defer bl.lock.RUnlock()
+
+	var res []Ban
+	now := time.Now()
+
+	for target, be := range bl.bans {
+		if be.expires.After(now) {
+			res = append(res, Ban{Target: target, Expires: be.expires})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Target < res[j].Target })
+	return res
+}
+
+func (bl *banList) isBanned(pi *PeerInfo) bool {
+
+	bl.lock.RLock()
+	defer bl.lock.RUnlock()
+
+	if len(bl.bans) == 0 {
+		return false
+	}
+
+	now := time.Now()
+
+	if be := bl.bans[pi.GetServerId()]; be != nil && be.net == nil && be.expires.After(now) {
+		banrejects.Add(1)
+		return true
+	}
+
+	for _, ni := range pi.GetNetInfo() {
+		if bl.addrBannedLocked(ni.GetAddr(), now) {
+			banrejects.Add(1)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (bl *banList) addrBanned(addr string) bool {
+
+	bl.lock.RLock()
+	defer bl.lock.RUnlock()
+
+	return bl.addrBannedLocked(addr, time.Now())
+}
+
+// caller must hold lock
+func (bl *banList) addrBannedLocked(addr string, now time.Time) bool {
+
+	if len(bl.bans) == 0 {
+		return false
+	}
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	for _, be := range bl.bans {
+		if be.net != nil && be.expires.After(now) && be.net.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (bl *banList) cleanup() {
+
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+
+	now := time.Now()
+
+	for target, be := range bl.bans {
+		if be.expires.Before(now) {
+			delete(bl.bans, target)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 23:20 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForceLeave(t *testing.T) {
+
+	a := tNewDB("u12-r14.phlccs1.example.com", 1234)
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+	c := tNewDB("u14-r14.phlccs1.example.com", 1234)
+
+	stale := c.Myself()
+	a.Update(c.Myself())
+	b.Update(c.Myself())
+
+	if err := a.ForceLeave("nobody"); err != ErrUnknownPeer {
+		t.Fatalf("expected error, got %v", err)
+	}
+	if err := a.ForceLeave(c.Id()); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	// gossip a => b. transports expect their own type
+	a.ForAllData(func(id string, isup bool, d interface{}) {
+		b.Update(d.(*tPeer))
+	})
+
+	ts := b.Tombstones()
+	if b.Get(c.Id()) != nil || len(ts) != 1 || ts[0].Reason != TOMB_EVICTED {
+		t.Fatalf("not evicted %+v", ts)
+	}
+
+	// old news does not bring it back
+	b.Update(stale)
+	if b.Get(c.Id()) != nil {
+		t.Fatalf("resurrected")
+	}
+
+	// neither does new news, while the eviction lasts
+	a.UpdateSceptical(c.Myself())
+	b.Update(c.Myself())
+	if a.Get(c.Id()) != nil || b.Get(c.Id()) != nil {
+		t.Fatalf("evicted peer came back")
+	}
+
+	// it is passed along in delta exchanges too
+	d := tNewDB("u15-r14.phlccs1.example.com", 1234)
+	d.Update(c.Myself())
+
+	a.RecvDigest(d.Myself(), d.digest(), func(id string, isup bool, x interface{}) {
+		d.Update(x.(*tPeer))
+	})
+	if d.Get(c.Id()) != nil {
+		t.Fatalf("eviction not passed along")
+	}
+}
+
+func TestBan(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	rec := func(id string, addr string) PeerImport {
+		return &tPeer{tRecord(b, id, STATUS_UP, addr)}
+	}
+
+	b.Update(rec("mrtesty@bad1", "10.5.0.1:1234"))
+	b.Update(rec("mrtesty@good", "10.6.0.1:1234"))
+
+	b.Ban("10.5.0.0/16", time.Minute)
+	b.Ban("mrtesty@bad2", 0)
+
+	if b.Get("mrtesty@bad1") != nil || b.Get("mrtesty@good") == nil {
+		t.Fatalf("ban did not remove peer")
+	}
+
+	b.UpdateSceptical(rec("mrtesty@bad2", "10.7.0.1:1234"))
+	b.Update(rec("mrtesty@bad3", "10.5.9.9:1234"))
+
+	if b.Get("mrtesty@bad2") != nil || b.Get("mrtesty@bad3") != nil {
+		t.Fatalf("accepted banned peer")
+	}
+	if !b.bans.addrBanned("10.5.1.1:1234") || b.bans.addrBanned("10.6.0.1:1234") {
+		t.Fatalf("addr ban")
+	}
+
+	b.Unban("mrtesty@bad2")
+	b.UpdateSceptical(rec("mrtesty@bad2", "10.7.0.1:1234"))
+
+	if b.Get("mrtesty@bad2") == nil {
+		t.Fatalf("unban failed")
+	}
+}
+
+func TestAdminHTTP(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	post := func(form string) int {
+		req := httptest.NewRequest("POST", "/admin", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		b.AdminHTTP(w, req)
+		return w.Code
+	}
+
+	if code := post("op=ban&target=10.9.0.0/16&for=1h"); code != 200 {
+		t.Fatalf("ban %d", code)
+	}
+	if code := post("op=ban&target=10.9.0.0/16&for=soon"); code != 400 {
+		t.Fatalf("bad duration %d", code)
+	}
+	if code := post("op=leave&id=nobody"); code != 404 {
+		t.Fatalf("leave %d", code)
+	}
+	if code := post("op=explode"); code != 400 {
+		t.Fatalf("invalid op %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	b.AdminHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+
+	var st adminStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &st); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if len(st.Bans) != 1 || st.Bans[0].Target != "10.9.0.0/16" {
+		t.Fatalf("bans %+v", st.Bans)
+	}
+}
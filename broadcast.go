@@ -0,0 +1,172 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 10:12 (EDT)
+// Function: user defined broadcast messages, piggybacked on gossip
+
+package kibitz
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+const (
+	MAXBCAST  = 16 // max messages piggybacked per exchange
+	BCASTMULT = 2  // retransmit each message BCASTMULT * log(N) times
+)
+
+var ErrTooBig = errors.New("kibitz: broadcast is too large")
+
+var bcastsent = expvar.NewInt("kibitz_bcast_sent")
+var bcastrecv = expvar.NewInt("kibitz_bcast_recv")
+
+type BroadcastHandler func(origin string, name string, payload []byte)
+
+type bcastPend struct {
+	msg  *Broadcast
+	sent int
+}
+
+type bcaster struct {
+	lock    sync.Mutex
+	npeers  int
+	pending map[string]*bcastPend // still being retransmitted
+	seen    map[string]uint64     // id => expire time
+	handler map[string]BroadcastHandler
+}
+
+func bcasterNew() *bcaster {
+	return &bcaster{
+		pending: make(map[string]*bcastPend),
+		seen:    make(map[string]uint64),
+		handler: make(map[string]BroadcastHandler),
+	}
+}
+
+// Broadcast sends a message to every node in the cluster
+// the message is delivered (once) to the handler registered for name
+func (pdb *DB) Broadcast(name string, payload []byte, ttl time.Duration) error {
+
+	// peers would refuse our updates while it is attached
+	if over(len(payload), pdb.limits.MaxPayload) || over(len(name), pdb.limits.MaxFieldLen) {
+		return ErrTooBig
+	}
+
+	now := pdb.clock.Inc().Uint64()
+
+	b := &Broadcast{
+		Id:         fmt.Sprintf("%s/%d", pdb.id, now),
+		Origin:     pdb.id,
+		Name:       name,
+		Payload:    payload,
+		TimeExpire: now + uint64(ttl),
+	}
+
+	dl.Debug("broadcast %s %s", b.Id, name)
+	pdb.bcast.recv(b, now)
+	return nil
+}
+
+// HandleBroadcast registers the handler for messages named name
+func (pdb *DB) HandleBroadcast(name string, fnc BroadcastHandler) {
+	pdb.bcast.lock.Lock()
+	defer pdb.bcast.lock.Unlock()
+
+	pdb.bcast.handler[name] = fnc
+}
+
+// process any messages attached to a received update
+func (pdb *DB) recvBroadcast(pi *PeerInfo) {
+
+	if len(pi.Broadcast) == 0 {
+		return
+	}
+
+	now := pdb.clock.Now().Uint64()
+
+	for _, b := range pi.Broadcast {
+		pdb.bcast.recv(b, now)
+	}
+
+	// do not store or forward with the peer's data
+	pi.Broadcast = nil
+}
+
+// ################################################################
+
+func (bc *bcaster) recv(b *Broadcast, now uint64) {
+
+	if b.GetId() == "" || b.GetTimeExpire() < now {
+		return
+	}
+
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if _, ok := bc.seen[b.Id]; ok {
+		// dupe
+		return
+	}
+
+	bcastrecv.Add(1)
+	bc.seen[b.Id] = b.TimeExpire
+	bc.pending[b.Id] = &bcastPend{msg: b}
+
+	if h := bc.handler[b.GetName()]; h != nil {
+		go h(b.GetOrigin(), b.GetName(), b.GetPayload())
+	}
+}
+
+// messages to attach to an outgoing exchange
+func (bc *bcaster) outgoing(now uint64) []*Broadcast {
+
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if len(bc.pending) == 0 {
+		return nil
+	}
+
+	// ~ log(N) rounds
+	limit := BCASTMULT * bits.Len(uint(bc.npeers+1))
+
+	var res []*Broadcast
+
+	for id, bp := range bc.pending {
+		if bp.msg.TimeExpire < now {
+			delete(bc.pending, id)
+			continue
+		}
+		if len(res) >= MAXBCAST {
+			continue
+		}
+
+		res = append(res, bp.msg)
+		bcastsent.Add(1)
+		bp.sent++
+
+		if bp.sent >= limit {
+			delete(bc.pending, id)
+		}
+	}
+
+	return res
+}
+
+func (bc *bcaster) cleanup(now uint64, npeers int) {
+
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	bc.npeers = npeers
+
+	for id, exp := range bc.seen {
+		if exp < now {
+			delete(bc.seen, id)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 10:41 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBroadcast(t *testing.T) {
+
+	a := tNewDB("u12-r14.phlccs1.example.com", 1234)
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	got := make(chan string, 10)
+	b.HandleBroadcast("flush", func(origin string, name string, payload []byte) {
+		got <- origin + " " + string(payload)
+	})
+
+	if err := a.Broadcast("flush", []byte("tenant X"), time.Minute); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	// peers would refuse it
+	if err := a.Broadcast("flush", make([]byte, MAXPAYLOAD+1), time.Minute); err != ErrTooBig {
+		t.Fatalf("expected too big, got %v", err)
+	}
+
+	// looking at ourself is not a transmission
+	for i := 0; i < 100; i++ {
+		if len(a.MyInfo().Broadcast) != 0 {
+			t.Fatalf("attached to myinfo")
+		}
+	}
+
+	// deliver the same message twice
+	b.UpdateSceptical(a.myselfToSend())
+	b.UpdateSceptical(a.myselfToSend())
+
+	select {
+	case m := <-got:
+		if m != a.Id()+" tenant X" {
+			t.Fatalf("got %s", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("not delivered")
+	}
+
+	select {
+	case m := <-got:
+		t.Fatalf("delivered twice %s", m)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if b.Get(a.Id()) == nil {
+		t.Fatalf("sender refused")
+	}
+
+	// and it should be retransmitted onward
+	if len(b.myselfToSend().GetPeerInfo().Broadcast) != 1 {
+		t.Fatalf("not forwarded")
+	}
+}
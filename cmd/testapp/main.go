@@ -53,7 +53,8 @@ func main() {
 		ServerID: "testapp",
 		Service:  []enginz.Service{{Addr: fmt.Sprintf(":%d", port)}},
 		Handler: enginz.Routes{
-			"/kibitz": recvKibitz, // api endpoint
+			"/kibitz":       recvKibitz,    // api endpoint
+			"/kibitz/admin": pdb.AdminHTTP, // operator endpoint
 		},
 	}
 
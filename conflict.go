@@ -0,0 +1,136 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 13:24 (EDT)
+// Function: detect multiple servers using the same id
+
+package kibitz
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+)
+
+const CONFLICTQUIET = time.Minute // report a given conflict at most this often
+
+var ErrDuplicateId = errors.New("kibitz: server id is already in use")
+
+var idconflicts = expvar.NewInt("kibitz_id_conflicts")
+
+type dupCheck struct {
+	lock sync.Mutex
+	seen bool
+	last time.Time
+}
+
+// a newer record, but from an older configuration, with different addresses?
+// two (or more) servers are taking turns updating the same id
+func isConflict(curr *PeerInfo, pi *PeerInfo) bool {
+
+	if pi.GetTimeConf() >= curr.GetTimeConf() {
+		return false
+	}
+
+	return !sameAddrs(curr.GetNetInfo(), pi.GetNetInfo())
+}
+
+func sameAddrs(a []*NetInfo, b []*NetInfo) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	addrs := make(map[string]bool)
+	for _, ni := range a {
+		addrs[ni.GetAddr()] = true
+	}
+	for _, ni := range b {
+		if !addrs[ni.GetAddr()] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// caller must hold lock
+func (p *Peer) checkConflict(pi *PeerInfo) {
+
+	if !isConflict(p.info, pi) {
+		return
+	}
+
+	idconflicts.Add(1)
+
+	if time.Since(p.conflict) < CONFLICTQUIET {
+		return
+	}
+	p.conflict = time.Now()
+
+	dl.Verbose("server id conflict %s", p.id)
+
+	p.pdb.event(&Event{
+		Type:   EVENT_ID_CONFLICT,
+		Id:     p.id,
+		Before: p.pdb.exportInfo(p.info),
+		After:  p.pdb.exportInfo(pi),
+	})
+}
+
+// is someone else using our id?
+func (pdb *DB) checkSelfConflict(pi *PeerInfo) {
+
+	if pdb.isMyConf(pi.GetTimeConf()) {
+		// ours
+		return
+	}
+	if pi.GetTimeCreated() < pdb.bootTime || PeerStatus(pi.GetStatusCode()) != STATUS_UP {
+		// a previous incarnation
+		return
+	}
+
+	idconflicts.Add(1)
+
+	pdb.dup.lock.Lock()
+	defer pdb.dup.lock.Unlock()
+
+	pdb.dup.seen = true
+
+	if time.Since(pdb.dup.last) < CONFLICTQUIET {
+		return
+	}
+	pdb.dup.last = time.Now()
+
+	dl.Verbose("server id conflict - my id %s is in use elsewhere", pdb.id)
+
+	pdb.event(&Event{
+		Type:  EVENT_ID_CONFLICT,
+		Id:    pdb.id,
+		After: pdb.exportInfo(pi),
+	})
+}
+
+// IdConflict reports whether we have seen another server using our id
+func (pdb *DB) IdConflict() bool {
+	pdb.dup.lock.Lock()
+	defer pdb.dup.lock.Unlock()
+
+	return pdb.dup.seen
+}
+
+// ask the seeds about ourself
+func (pdb *DB) checkUnique() error {
+
+	for _, addr := range pdb.seed {
+		if pdb.IsOwnAddr(addr) {
+			continue
+		}
+		pdb.kibitzWith(addr, "[seed]", "[seed]")
+	}
+
+	if pdb.IdConflict() {
+		return ErrDuplicateId
+	}
+	return nil
+}
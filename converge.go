@@ -0,0 +1,118 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 12:02 (EDT)
+// Function: cluster convergence fingerprint
+
+package kibitz
+
+import (
+	"context"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+)
+
+// order independent hash over the set of (id, status) that we believe in
+// caller must hold lock
+func (pdb *DB) updateFingerprint() uint64 {
+
+	fp := fingerprintOf(pdb.id, int32(STATUS_UP))
+
+	for _, p := range pdb.kibitzers {
+		p.lock.Lock()
+		p.fpart = p.fingerprint()
+		fp += p.fpart
+		p.lock.Unlock()
+	}
+
+	atomic.StoreUint64(&pdb.fprint, fp)
+	return fp
+}
+
+// the peer's status changed, adjust the fingerprint to match
+// caller must hold p.lock
+func (p *Peer) refingerprint() {
+
+	fp := p.fingerprint()
+	if fp != p.fpart {
+		atomic.AddUint64(&p.pdb.fprint, fp-p.fpart)
+		p.fpart = fp
+	}
+}
+
+// this peer's part of the fingerprint
+// caller must hold p.lock
+func (p *Peer) fingerprint() uint64 {
+
+	switch p.status {
+	case STATUS_SCEPTICAL, STATUS_DEAD:
+		return 0
+	}
+	if p.info.GetSubsystem() != p.pdb.sys {
+		return 0
+	}
+
+	return fingerprintOf(p.id, p.info.GetStatusCode())
+}
+
+func fingerprintOf(id string, st int32) uint64 {
+
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	h.Write([]byte{0, byte(st)})
+
+	return h.Sum64()
+}
+
+func (pdb *DB) Fingerprint() uint64 {
+	return atomic.LoadUint64(&pdb.fprint)
+}
+
+// ConvergedWith returns the fraction of up peers whose fingerprint matches ours
+func (pdb *DB) ConvergedWith() float64 {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	fp := pdb.updateFingerprint()
+	nup := 0
+	nmatch := 0
+
+	for _, p := range pdb.kibitzers {
+		p.lock.Lock()
+		if p.status == STATUS_UP {
+			nup++
+			if p.info.GetFingerprint() == fp {
+				nmatch++
+			}
+		}
+		p.lock.Unlock()
+	}
+
+	if nup == 0 {
+		if len(pdb.seed) == 0 {
+			// all alone
+			return 1
+		}
+		return 0
+	}
+
+	return float64(nmatch) / float64(nup)
+}
+
+// WaitConverged blocks until at least fraction of our peers agree with us
+func (pdb *DB) WaitConverged(ctx context.Context, fraction float64) error {
+
+	for {
+		if pdb.ConvergedWith() >= fraction {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			continue
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 12:31 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConverge(t *testing.T) {
+
+	a := tNewDB("u12-r14.phlccs1.example.com", 1234)
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	a.Update(b.Myself())
+	b.Update(a.Myself())
+
+	// b sent a fingerprint from before it knew about a
+	if a.ConvergedWith() != 0 {
+		t.Fatalf("converged too soon")
+	}
+
+	// exchange again, now with current fingerprints
+	a.Update(b.Myself())
+
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Fatalf("fingerprints differ")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := a.WaitConverged(ctx, 1); err != nil {
+		t.Fatalf("not converged: %v", err)
+	}
+
+	// kept current between cleanups
+	fp := a.Fingerprint()
+	for i := 0; i <= MAXFAIL; i++ {
+		a.PeerDn(b.Id())
+	}
+	if a.Fingerprint() == fp {
+		t.Fatalf("fingerprint not updated")
+	}
+	if a.Fingerprint() != a.updateFingerprint() {
+		t.Fatalf("fingerprint out of sync")
+	}
+
+	a.PeerUp(b.Id())
+	if a.Fingerprint() != fp {
+		t.Fatalf("fingerprint not restored")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 11:03 (EDT)
+// Function: push-pull delta exchange
+
+package kibitz
+
+import (
+	"expvar"
+	"time"
+)
+
+// instead of sending the entire peer table each round:
+//   client sends a digest of what it has
+//   server replies with what the client is missing + a digest of what it wants
+//   client sends what the server wants
+
+// transports that implement this will use delta exchanges
+type deltaer interface {
+	SendDigest(string, time.Duration, PeerImport, *Digest) ([]PeerImport, *Digest, error)
+	SendDelta(string, time.Duration, PeerImport, []PeerImport) error
+}
+
+var deltarecs = expvar.NewInt("kibitz_delta_records")
+
+// client side
+func (pdb *DB) sendDelta(dx deltaer, addr string, myself PeerImport) ([]PeerImport, error) {
+
+	peerList, want, err := dx.SendDigest(addr, TIMEOUT, myself, pdb.digest())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(want.GetEntry()) != 0 {
+		push := pdb.deltaFor(want)
+
+		if len(push) != 0 {
+			err = dx.SendDelta(addr, TIMEOUT, pdb.Myself(), push)
+			if err != nil {
+				dl.Debug("delta push failed %v", err)
+			}
+		}
+	}
+
+	return peerList, nil
+}
+
+// RecvDigest handles the first half of a delta exchange.
+// fnc is called for each peer that the requester does not have, or has an older copy of.
+// returns a digest of the records we want from the requester.
+func (pdb *DB) RecvDigest(px PeerImport, dig *Digest, fnc func(string, bool, interface{})) *Digest {
+
+	reqId := ""
+
+	if px != nil {
+		if pdb.IsBanned(px) {
+			return &Digest{}
+		}
+		reqId = px.GetPeerInfo().GetServerId()
+		pdb.UpdateSceptical(px)
+	}
+
+	theirs := make(map[string]*DigestEntry)
+	for _, e := range dig.GetEntry() {
+		theirs[e.GetServerId()] = e
+	}
+
+	want := &Digest{}
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	for _, p := range pdb.allpeers {
+		if p.id == reqId {
+			continue
+		}
+
+		e := theirs[p.id]
+		if e == nil || p.newerThan(e) {
+			deltarecs.Add(1)
+			fnc(p.id, p.isAvailable(), p.GetData())
+		}
+	}
+
+	// evictions
+	for id, t := range pdb.tombs {
+		if d := pdb.evictionData(t); d != nil {
+			fnc(id, false, d)
+		}
+	}
+
+	now := time.Now()
+
+	for id, e := range theirs {
+		if id == pdb.id {
+			continue
+		}
+		if t := pdb.tombs[id]; t != nil && t.rejects(e.GetTimeCreated(), now) {
+			continue
+		}
+
+		p := pdb.allpeers[id]
+		if p == nil || p.olderThan(e) {
+			want.Entry = append(want.Entry, e)
+		}
+	}
+
+	// and myself
+	fnc(pdb.id, isAvailable(STATUS_UP, pdb.Health()), pdb.myselfToSend())
+
+	return want
+}
+
+// RecvDelta handles the second half of a delta exchange
+func (pdb *DB) RecvDelta(px PeerImport, delta []PeerImport) {
+
+	src := ""
+
+	if px != nil {
+		src = px.GetPeerInfo().GetServerId()
+		pdb.UpdateSceptical(px)
+	}
+
+	pdb.updateFrom(src, delta)
+}
+
+// ################################################################
+
+func (pdb *DB) digest() *Digest {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	dig := &Digest{}
+
+	for _, p := range pdb.allpeers {
+		dig.Entry = append(dig.Entry, p.digestEntry())
+	}
+
+	return dig
+}
+
+func (pdb *DB) deltaFor(want *Digest) []PeerImport {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	var res []PeerImport
+
+	for _, e := range want.GetEntry() {
+		p := pdb.allpeers[e.GetServerId()]
+		if p == nil {
+			continue
+		}
+		if d, ok := p.GetData().(PeerImport); ok {
+			res = append(res, d)
+		}
+	}
+
+	deltarecs.Add(int64(len(res)))
+	return res
+}
+
+func (p *Peer) digestEntry() *DigestEntry {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return &DigestEntry{
+		ServerId:    p.id,
+		TimeCreated: p.info.GetTimeCreated(),
+		TimeChecked: p.info.GetTimeChecked(),
+	}
+}
+
+// is our copy newer than theirs?
+// same rule as Peer.Update. a newer TimeChecked alone would be discarded
+func (p *Peer) newerThan(e *DigestEntry) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.info.GetTimeCreated() > e.GetTimeCreated()
+}
+
+// is our copy older than theirs?
+func (p *Peer) olderThan(e *DigestEntry) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.info.GetTimeCreated() < e.GetTimeCreated()
+}
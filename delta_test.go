@@ -0,0 +1,70 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 11:40 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"testing"
+)
+
+func TestDelta(t *testing.T) {
+
+	a := tNewDB("u12-r14.phlccs1.example.com", 1234)
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+	c := tNewDB("u14-r14.phlccs1.example.com", 1234)
+
+	b.Update(&tPeer{c.MyInfo()})
+
+	// a knows nothing, should get everything
+	var got []string
+	collect := func(id string, isup bool, d interface{}) {
+		got = append(got, id)
+		a.Update(d.(PeerImport))
+	}
+
+	want := b.RecvDigest(a.Myself(), a.digest(), collect)
+
+	if len(got) != 2 || len(want.GetEntry()) != 0 {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// now a is up to date, should only get b
+	got = nil
+	want = b.RecvDigest(a.Myself(), a.digest(), collect)
+
+	if len(got) != 1 || got[0] != b.Id() || len(want.GetEntry()) != 0 {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// a learns something newer, b should want it
+	a.Update(&tPeer{c.MyInfo()})
+	got = nil
+	want = b.RecvDigest(a.Myself(), a.digest(), collect)
+
+	if len(want.GetEntry()) != 1 || want.Entry[0].GetServerId() != c.Id() {
+		t.Fatalf("want %v", want)
+	}
+
+	b.RecvDelta(a.Myself(), a.deltaFor(want))
+	want = b.RecvDigest(a.Myself(), a.digest(), collect)
+
+	if len(want.GetEntry()) != 0 {
+		t.Fatalf("want %v", want)
+	}
+
+	// both check c themselves. the same record, checked at different times
+	a.PeerUp(c.Id())
+	b.PeerUp(c.Id())
+	b.PeerUp(c.Id())
+
+	for i := 0; i < 3; i++ {
+		got = nil
+		want = b.RecvDigest(a.Myself(), a.digest(), collect)
+
+		if len(got) != 1 || got[0] != b.Id() || len(want.GetEntry()) != 0 {
+			t.Fatalf("round %d: got %v, want %v", i, got, want)
+		}
+	}
+}
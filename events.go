@@ -0,0 +1,70 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 13:10 (EDT)
+// Function: notify the application of interesting things
+
+package kibitz
+
+type EventType int
+
+const (
+	EVENT_ID_CONFLICT    EventType = 1 // two servers are using the same id
+	EVENT_CONFIG_CHANGED EventType = 2 // a peer changed its configuration
+	EVENT_NET_UP         EventType = 3 // a network is reachable again
+	EVENT_NET_STALE      EventType = 4 // a network has not been used successfully recently
+)
+
+type Event struct {
+	Type   EventType
+	Id     string
+	Before *Export
+	After  *Export
+	Net    *NetStatus // for network events
+}
+
+// transports that implement this will be notified of events
+type eventer interface {
+	Event(*Event)
+}
+
+func (pdb *DB) event(e *Event) {
+
+	dl.Debug("event %s %s", e.Type, e.Id)
+
+	if ev, ok := pdb.iface.(eventer); ok {
+		go ev.Event(e)
+	}
+}
+
+// check for networks going up or stale
+func (pdb *DB) checkNetworks() {
+
+	for _, ns := range pdb.nmon.changes() {
+		ns := ns
+		e := &Event{Type: EVENT_NET_UP, Id: pdb.id, Net: &ns}
+
+		if ns.IsUp {
+			dl.Verbose("network %s is up", ns.Name)
+		} else {
+			dl.Verbose("network %s is stale", ns.Name)
+			e.Type = EVENT_NET_STALE
+		}
+
+		pdb.event(e)
+	}
+}
+
+func (t EventType) String() string {
+	switch t {
+	case EVENT_ID_CONFLICT:
+		return "ID_CONFLICT"
+	case EVENT_CONFIG_CHANGED:
+		return "CONFIG_CHANGED"
+	case EVENT_NET_UP:
+		return "NET_UP"
+	case EVENT_NET_STALE:
+		return "NET_STALE"
+	}
+
+	return "UNKNOWN"
+}
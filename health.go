@@ -0,0 +1,161 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 11:00 (EDT)
+// Function: local health checks
+
+package kibitz
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const HEALTHCHECK = 10 * time.Second // run health checks how often?
+
+// sent in its own field. the status stays UP, so older versions still see us as up
+type Health int
+
+const (
+	HEALTH_OK       Health = 0
+	HEALTH_DEGRADED Health = 1 // working, but not well. prefer others
+	HEALTH_DRAINING Health = 2 // going away. do not send new work
+)
+
+type healthChecks struct {
+	lock   sync.RWMutex
+	conf   func() Health
+	checks map[string]func() Health
+	curr   Health
+}
+
+func healthChecksNew(conf func() Health) *healthChecks {
+	return &healthChecks{
+		conf:   conf,
+		checks: make(map[string]func() Health),
+	}
+}
+
+// RegisterHealthCheck adds a check. the worst result of all checks is advertised.
+func (pdb *DB) RegisterHealthCheck(name string, fnc func() Health) {
+
+	pdb.health.lock.Lock()
+	defer pdb.health.lock.Unlock()
+
+	if fnc == nil {
+		delete(pdb.health.checks, name)
+		return
+	}
+	pdb.health.checks[name] = fnc
+}
+
+// Health returns our current health, as advertised
+func (pdb *DB) Health() Health {
+
+	pdb.health.lock.RLock()
+	defer pdb.health.lock.RUnlock()
+
+	return pdb.health.curr
+}
+
+// CheckHealth runs the checks now. returns the result
+func (pdb *DB) CheckHealth() Health {
+
+	h := pdb.health.run()
+
+	pdb.health.lock.Lock()
+	prev := pdb.health.curr
+	pdb.health.curr = h
+	pdb.health.lock.Unlock()
+
+	if h != prev {
+		dlme.Verbose("health changed %s -> %s", prev, h)
+		// tell everyone
+		pdb.rumor(pdb.id)
+	}
+
+	return h
+}
+
+func (hc *healthChecks) run() Health {
+
+	hc.lock.RLock()
+	fncs := make([]func() Health, 0, len(hc.checks)+1)
+	if hc.conf != nil {
+		fncs = append(fncs, hc.conf)
+	}
+	for _, f := range hc.checks {
+		fncs = append(fncs, f)
+	}
+	hc.lock.RUnlock()
+
+	worst := HEALTH_OK
+	for _, f := range fncs {
+		if h := f(); h > worst {
+			worst = h
+		}
+	}
+
+	return worst
+}
+
+func (pdb *DB) healthCheckLoop() {
+
+	defer pdb.done.Done()
+
+	for {
+		pdb.CheckHealth()
+
+		select {
+		case <-pdb.stop:
+			return
+		case <-time.After(pdb.healthFreq):
+		}
+	}
+}
+
+func (h Health) String() string {
+	switch h {
+	case HEALTH_OK:
+		return "OK"
+	case HEALTH_DEGRADED:
+		return "DEGRADED"
+	case HEALTH_DRAINING:
+		return "DRAINING"
+	}
+	return "UNKNOWN"
+}
+
+// up, and willing to take new work
+func isAvailable(st PeerStatus, h Health) bool {
+	return st == STATUS_UP && h != HEALTH_DRAINING
+}
+
+func (p *Peer) isAvailable() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return isAvailable(p.status, Health(p.info.GetHealth()))
+}
+
+// ################################################################
+
+// Available returns the up peers of a subsystem, healthy ones first.
+// draining peers are never included, degraded peers only if wanted.
+func (pdb *DB) Available(sys string, withDegraded bool) []*Export {
+
+	var res []*Export
+
+	pdb.ForAllExport(func(pe *Export) {
+		if pe.Sys != sys || !pe.IsUp || pe.IsDraining {
+			return
+		}
+		if pe.IsDegraded && !withDegraded {
+			return
+		}
+		res = append(res, pe)
+	})
+
+	sort.SliceStable(res, func(i, j int) bool { return !res[i].IsDegraded && res[j].IsDegraded })
+	return res
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 11:40 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+
+	dbok := true
+
+	a := New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    "u12-r14.phlccs1.example.com",
+		Port:        1234,
+		Iface:       tIface{},
+		HealthCheck: func() Health {
+			if dbok {
+				return HEALTH_OK
+			}
+			return HEALTH_DEGRADED
+		},
+	})
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	if a.CheckHealth() != HEALTH_OK || a.MyInfo().GetHealth() != int32(HEALTH_OK) {
+		t.Fatalf("expected ok")
+	}
+
+	dbok = false
+	if a.CheckHealth() != HEALTH_DEGRADED || a.MyInfo().GetHealth() != int32(HEALTH_DEGRADED) {
+		t.Fatalf("expected degraded")
+	}
+
+	b.Update(a.Myself())
+	pe := b.Get(a.Id()).GetExport()
+
+	if pe.Status != STATUS_UP || !pe.IsUp || !pe.IsDegraded {
+		t.Fatalf("export %s up %v degraded %v", pe.Status, pe.IsUp, pe.IsDegraded)
+	}
+
+	// still degraded after we check it
+	b.PeerUp(a.Id())
+	if pe := b.Get(a.Id()).GetExport(); !pe.IsDegraded {
+		t.Fatalf("lost degraded status")
+	}
+
+	if len(b.Available("mrtesty", false)) != 0 || len(b.Available("mrtesty", true)) != 1 {
+		t.Fatalf("available")
+	}
+
+	// worst check wins
+	a.RegisterHealthCheck("shutdown", func() Health { return HEALTH_DRAINING })
+	if a.CheckHealth() != HEALTH_DRAINING {
+		t.Fatalf("expected draining")
+	}
+
+	// older versions only look at the status
+	if a.MyInfo().GetStatusCode() != int32(STATUS_UP) {
+		t.Fatalf("draining peer sent status %d", a.MyInfo().GetStatusCode())
+	}
+
+	b.Update(a.Myself())
+	if pe := b.Get(a.Id()).GetExport(); pe.IsUp || !pe.IsDraining || len(b.Available("mrtesty", true)) != 0 {
+		t.Fatalf("draining peer is up")
+	}
+
+	// transports are told the same
+	for _, db := range []*DB{a, b} {
+		db.ForAllData(func(id string, isup bool, d interface{}) {
+			if id == a.Id() && isup {
+				t.Fatalf("draining peer is up to the transport")
+			}
+		})
+	}
+
+	a.RegisterHealthCheck("shutdown", nil)
+	dbok = true
+	if a.CheckHealth() != HEALTH_OK {
+		t.Fatalf("expected ok")
+	}
+
+	b.Update(a.Myself())
+	if pe := b.Get(a.Id()).GetExport(); !pe.IsUp || pe.IsDegraded || pe.IsDraining {
+		t.Fatalf("expected healthy")
+	}
+}
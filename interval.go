@@ -0,0 +1,111 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 20:25 (EDT)
+// Function: how often to kibitz
+
+package kibitz
+
+import (
+	"expvar"
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+const (
+	SPREADTIME   = 100 * time.Second // how long should news take to reach everyone, in a large cluster
+	SPREADROUNDS = 10                // rounds for news to reach everyone, in a large (~1000) cluster
+	MININTERVAL  = time.Second
+	MAXINTERVAL  = 10 * time.Second
+	CHURNTIME    = time.Minute // how quickly the churn rate decays
+	CHURNGAIN    = 10          // go this much faster when the entire cluster changes
+)
+
+var curinterval = expvar.NewInt("kibitz_interval_ms")
+
+// recent status changes, exponentially decayed
+type churnMeter struct {
+	lock sync.Mutex
+	rate float64
+	last time.Time
+}
+
+func (pdb *DB) interval() time.Duration {
+
+	pdb.lock.RLock()
+	nkibitz := len(pdb.kibitzers)
+	nskept := len(pdb.skeptical)
+	pdb.lock.RUnlock()
+
+	delay := pdb.intervalFor(nkibitz, pdb.churn.get(time.Now()))
+
+	if nkibitz == 0 || nskept != 0 {
+		// faster at startup
+		delay = pdb.minInterval
+	}
+
+	curinterval.Set(int64(delay / time.Millisecond))
+
+	if _, ok := pdb.selector.(*RoundRobin); ok {
+		probebound.Set(int64(rrBound(nkibitz, len(pdb.seed), nskept)) * int64(delay/time.Millisecond))
+	}
+
+	return delay
+}
+
+// news reaches everyone in ~ log2(N) rounds. with the interval also growing
+// with log2(N), small clusters stay quick, and large ones back off, taking
+// spreadTime for news to reach everyone at SPREADROUNDS.
+// and faster in proportion to the fraction of the cluster recently changed.
+func (pdb *DB) intervalFor(nkibitz int, churn float64) time.Duration {
+
+	rounds := bits.Len(uint(nkibitz)) + 1
+	delay := pdb.spreadTime * time.Duration(rounds) / (SPREADROUNDS * SPREADROUNDS)
+
+	if nkibitz > 0 {
+		delay = time.Duration(float64(delay) / (1 + CHURNGAIN*churn/float64(nkibitz)))
+	}
+
+	if delay < pdb.minInterval {
+		delay = pdb.minInterval
+	}
+	if delay > pdb.maxInterval {
+		delay = pdb.maxInterval
+	}
+
+	return delay
+}
+
+// something changed
+func (pdb *DB) churned() {
+	pdb.churn.add(time.Now())
+}
+
+func (c *churnMeter) add(now time.Time) {
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.decay(now)
+	c.rate++
+}
+
+// ~ number of changes in the last CHURNTIME
+func (c *churnMeter) get(now time.Time) float64 {
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.decay(now)
+	return c.rate
+}
+
+// caller must hold lock
+func (c *churnMeter) decay(now time.Time) {
+
+	if !c.last.IsZero() {
+		c.rate *= math.Exp(-float64(now.Sub(c.last)) / float64(CHURNTIME))
+	}
+	c.last = now
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 14:10 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestInterval(t *testing.T) {
+
+	a := tNewDB("u12-r14.phlccs1.example.com", 1234)
+
+	// nobody to talk to
+	if d := a.interval(); d != MININTERVAL {
+		t.Fatalf("interval %v", d)
+	}
+
+	// small clusters are quick, large ones back off
+	curve := []struct {
+		n int
+		d time.Duration
+	}{
+		{1, 2 * time.Second},
+		{3, 3 * time.Second},
+		{5, 4 * time.Second},
+		{10, 5 * time.Second},
+		{20, 6 * time.Second},
+		{100, 8 * time.Second},
+		{300, 10 * time.Second},
+		{5000, MAXINTERVAL},
+	}
+	for _, c := range curve {
+		if d := a.intervalFor(c.n, 0); d != c.d {
+			t.Errorf("%d peers: interval %v, expected %v", c.n, d, c.d)
+		}
+	}
+
+	// faster, the more of the cluster is changing
+	if d := a.intervalFor(100, 10); d != 4*time.Second {
+		t.Errorf("churning: interval %v", d)
+	}
+	if d := a.intervalFor(100, 100); d != MININTERVAL {
+		t.Errorf("churning: interval %v", d)
+	}
+
+	for i := 0; i < 10; i++ {
+		a.Update(&tPeer{tRecord(a, fmt.Sprintf("mrtesty@peer%d", i), STATUS_UP)})
+	}
+
+	// just discovered all of them
+	if d := a.interval(); d != MININTERVAL {
+		t.Fatalf("interval %v", d)
+	}
+
+	// and then it settles down
+	if c := a.churn.get(time.Now().Add(5 * CHURNTIME)); c > 0.1 {
+		t.Fatalf("churn %v", c)
+	}
+}
@@ -36,103 +36,59 @@ func (pdb *DB) kibitzWithRandomPeer() {
 		return
 	}
 
+	if pdb.isRemote(natdom) {
+		pdb.relayWith(peerAddr, natdom, peerId)
+		return
+	}
+
+	pdb.kibitzWith(peerAddr, natdom, peerId)
+}
+
+func (pdb *DB) kibitzWith(peerAddr string, natdom string, peerId string) {
+
+	if pdb.bans.addrBanned(peerAddr) {
+		dl.Debug("kibitz with peer - skipping - banned %s %s", peerAddr, peerId)
+		return
+	}
+
 	dl.Debug("kibitz with peer %s (%s)", peerAddr, peerId)
 
-	myself := pdb.Myself()
+	myself := pdb.myselfToSend()
 
-	peerList, err := pdb.iface.Send(peerAddr, TIMEOUT, myself)
+	var peerList []PeerImport
+	var err error
+
+	if dx, ok := pdb.iface.(deltaer); ok {
+		peerList, err = pdb.sendDelta(dx, peerAddr, myself)
+	} else {
+		peerList, err = pdb.iface.Send(peerAddr, TIMEOUT, myself)
+	}
 
 	if err != nil {
 		dl.Debug(" => down err %v", err)
 		pdb.PeerDn(peerId)
+		pdb.addrResult(peerId, peerAddr, false)
 
 		clienterrs.Add(1)
 		return
 	}
 
 	// process response
-	for _, pd := range peerList {
-		pdb.Update(pd)
+	src := peerId
+	if src == "[seed]" {
+		src = peerAddr
 	}
+	pdb.updateFrom(src, peerList)
 
 	clientconns.Add(1)
 	pdb.PeerUp(peerId)
+	pdb.addrResult(peerId, peerAddr, true)
 	pdb.nmon.SetUp(natdom)
 }
 
-func (pdb *DB) getRandomPeer() *Peer {
-
-	pdb.lock.RLock()
-	defer pdb.lock.RUnlock()
-
-	oldLimit := time.Now().Add(OLDTIMER)
-
-	old := &randPeer{}
-	local := &randPeer{}
-	away := &randPeer{}
-	check := &randPeer{}
-	skept := &randPeer{}
-
-	nall := 0
-
-	for _, p := range pdb.kibitzers {
-		pe := p.GetExport()
-		nall++
-
-		if pe.Status == STATUS_MAYBEDN {
-			check.maybe(p)
-		}
-
-		if pe.LastTry.Before(oldLimit) {
-			old.maybe(p)
-		}
-
-		if pe.Datacenter == pdb.dc {
-			local.maybe(p)
-		} else {
-			away.maybe(p)
-		}
-	}
-
-	for _, p := range pdb.skeptical {
-		skept.maybe(p)
-	}
-
-	// first prefer anything sceptical
-	usePeer := skept.p
-
-	// then (maybe) anything pending
-	maybeUse(usePeer, check.p, 5)
-
-	// then (maybe) something about to expire
-	maybeUse(usePeer, old.p, 5)
-
-	// then (maybe) something far away
-	k := 5
-	if local.count < 5 {
-		// not very many locally, use more far
-		k = 2
-	}
-	if usePeer == nil && away.p != nil && random_n(k) == 0 {
-		usePeer = away.p
-	}
-
-	// otherwise prefer local
-	if usePeer == nil {
-		usePeer = local.p
-	}
-
-	// sometimes, use seed. so we can recover from a partition
-	if random_n(2*nall+2) == 0 {
-		usePeer = nil
-	}
-
-	return usePeer
-}
-
 func (pdb *DB) kibitzPeer() (string, string, string) {
 
-	p := pdb.getRandomPeer()
+	p := pdb.selectPeer()
 
 	if p != nil {
 		return pdb.useAddr(p)
@@ -147,31 +103,30 @@ func (pdb *DB) kibitzPeer() (string, string, string) {
 	return "", "", ""
 }
 
-func maybeUse(curr *Peer, nxt *Peer, n int) *Peer {
+func (pdb *DB) useAddr(p *Peer) (string, string, string) {
 
-	if nxt == nil {
-		return curr
-	}
-	if curr == nil || random_n(n) == 0 {
-		return nxt
+	// stick with what works (but sometimes mix it up)
+	if na := p.preferredAddr(); na != nil && random_n(20) != 0 {
+		dom := pdb.natdomOf(na)
+		if _, known := pdb.nmon.IsUp(dom); known {
+			return na.GetAddr(), dom, p.id
+		}
 	}
-	return curr
-}
-
-func (pdb *DB) useAddr(p *Peer) (string, string, string) {
 
 	down := &randNet{}
 	public := &randNet{}
 	private := &randNet{}
+	remote := &randNet{}
 
 	for _, na := range p.getAddrs() {
-		dom := na.GetNatdom()
+		dom := pdb.natdomOf(na)
 		isup, known := pdb.nmon.IsUp(dom)
 		if !known {
 			// remote private network
+			remote.maybe(na)
 			continue
 		}
-		if isup {
+		if isup && !p.addrFailing(na.GetAddr()) {
 			if dom == "" {
 				public.maybe(na)
 			} else {
@@ -185,16 +140,21 @@ func (pdb *DB) useAddr(p *Peer) (string, string, string) {
 	// preference (but sometimes mix it up): private (cheaper), public, down (to test if it is still down)
 
 	prefer := private.p
-	if prefer == nil || random_n(20) == 0 {
+	if public.p != nil && (prefer == nil || random_n(20) == 0) {
 		prefer = public.p
 	}
-	if prefer == nil || random_n(20) == 0 {
+	if down.p != nil && (prefer == nil || random_n(20) == 0) {
 		prefer = down.p
 	}
 
+	if prefer == nil && remote.p != nil && pdb.canRelay() {
+		// only reachable through a relay
+		prefer = remote.p
+	}
+
 	if prefer == nil {
 		return "", "", ""
 	}
 
-	return prefer.GetAddr(), prefer.GetNatdom(), p.id
+	return prefer.GetAddr(), pdb.natdomOf(prefer), p.id
 }
@@ -0,0 +1,199 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 09:15 (EDT)
+// Function: protect ourself from broken or hostile peers
+
+package kibitz
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+const (
+	MAXRECORDS   = 10000 // per exchange
+	MAXNEWPERMIN = 1000  // new peers per minute, from any one source. must allow for initial sync
+	MAXNETINFO   = 16
+	MAXFIELDLEN  = 256
+	MAXMETADATA  = 64
+	MAXPAYLOAD   = 8192        // per broadcast message
+	MAXREJECTSRC = 256         // distinct sources to record
+	NOSRC        = "unknown"   // reports from Update(), which does not say who told us
+	SCEPTSRC     = "sceptical" // peers contacting us, that we do not know yet
+)
+
+// zero means use the default, -1 means no limit
+type Limits struct {
+	MaxRecords   int
+	MaxNewPerMin int
+	MaxNetInfo   int
+	MaxFieldLen  int
+	MaxMetadata  int
+	MaxBroadcast int // messages per record
+	MaxPayload   int // per broadcast message
+	MaxVia       int // default MaxHops
+}
+
+var rejects = expvar.NewMap("kibitz_rejects")
+var rejectsrcs = expvar.NewMap("kibitz_reject_sources")
+
+type limiter struct {
+	Limits
+	lock    sync.Mutex
+	window  time.Time
+	newPeer map[string]int
+	nsrc    int
+	srcs    map[string]bool
+}
+
+func limiterNew(l Limits) *limiter {
+
+	lim := &limiter{
+		Limits:  l,
+		newPeer: make(map[string]int),
+		srcs:    make(map[string]bool),
+	}
+
+	lim.MaxRecords = limitDefault(lim.MaxRecords, MAXRECORDS)
+	lim.MaxNewPerMin = limitDefault(lim.MaxNewPerMin, MAXNEWPERMIN)
+	lim.MaxNetInfo = limitDefault(lim.MaxNetInfo, MAXNETINFO)
+	lim.MaxFieldLen = limitDefault(lim.MaxFieldLen, MAXFIELDLEN)
+	lim.MaxMetadata = limitDefault(lim.MaxMetadata, MAXMETADATA)
+	lim.MaxBroadcast = limitDefault(lim.MaxBroadcast, MAXBCAST)
+	lim.MaxPayload = limitDefault(lim.MaxPayload, MAXPAYLOAD)
+
+	return lim
+}
+
+func limitDefault(v int, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+func over(n int, limit int) bool {
+	return limit > 0 && n > limit
+}
+
+// ################################################################
+
+// process the records from one exchange
+func (pdb *DB) updateFrom(src string, recs []PeerImport) {
+
+	if over(len(recs), pdb.limits.MaxRecords) {
+		pdb.limits.reject(src, "records")
+		recs = recs[:pdb.limits.MaxRecords]
+	}
+
+	for _, px := range recs {
+		pdb.update(px, src)
+	}
+}
+
+// are the sizes sane?
+func (lim *limiter) sizeOK(pi *PeerInfo) string {
+
+	if over(len(pi.GetNetInfo()), lim.MaxNetInfo) {
+		return "netinfo"
+	}
+	if over(len(pi.GetMetadata()), lim.MaxMetadata) {
+		return "metadata"
+	}
+	if over(len(pi.GetBroadcast()), lim.MaxBroadcast) {
+		return "broadcast"
+	}
+	for _, b := range pi.GetBroadcast() {
+		if over(len(b.GetPayload()), lim.MaxPayload) {
+			return "payload"
+		}
+	}
+	if over(len(pi.GetViaPath()), lim.MaxVia) {
+		return "via"
+	}
+	if len(pi.GetViaPath()) != 0 && int(pi.GetHops()) < len(pi.GetViaPath()) {
+		// every relay adds itself and counts a hop. (older versions send neither)
+		return "hops"
+	}
+
+	if lim.MaxFieldLen <= 0 {
+		return ""
+	}
+
+	for _, f := range []string{pi.GetServerId(), pi.GetSubsystem(), pi.GetEnvironment(), pi.GetHostname(),
+		pi.GetDatacenter(), pi.GetRack(), pi.GetEvictedBy()} {
+		if over(len(f), lim.MaxFieldLen) {
+			return "field"
+		}
+	}
+	for _, ni := range pi.GetNetInfo() {
+		if over(len(ni.GetAddr()), lim.MaxFieldLen) || over(len(ni.GetNatdom()), lim.MaxFieldLen) {
+			return "field"
+		}
+	}
+	for _, v := range pi.GetViaPath() {
+		if over(len(v), lim.MaxFieldLen) {
+			return "field"
+		}
+	}
+	for k, v := range pi.GetMetadata() {
+		if over(len(k), lim.MaxFieldLen) || over(len(v), lim.MaxFieldLen) {
+			return "field"
+		}
+	}
+	for _, b := range pi.GetBroadcast() {
+		if over(len(b.GetId()), lim.MaxFieldLen) || over(len(b.GetOrigin()), lim.MaxFieldLen) || over(len(b.GetName()), lim.MaxFieldLen) {
+			return "field"
+		}
+	}
+
+	return ""
+}
+
+// may src tell us about another new peer?
+// unattributed reports share one allowance
+func (lim *limiter) allowNew(src string) bool {
+
+	if lim.MaxNewPerMin <= 0 {
+		return true
+	}
+	if src == "" {
+		src = NOSRC
+	}
+
+	lim.lock.Lock()
+	defer lim.lock.Unlock()
+
+	now := time.Now()
+	if now.Sub(lim.window) > time.Minute {
+		lim.window = now
+		lim.newPeer = make(map[string]int)
+	}
+
+	lim.newPeer[src]++
+	return lim.newPeer[src] <= lim.MaxNewPerMin
+}
+
+func (lim *limiter) reject(src string, reason string) {
+
+	if src == "" {
+		src = NOSRC
+	}
+
+	dl.Verbose("rejected update from %s: %s", src, reason)
+	rejects.Add(reason, 1)
+
+	lim.lock.Lock()
+	if !lim.srcs[src] {
+		if lim.nsrc >= MAXREJECTSRC {
+			src = "other"
+		} else {
+			lim.srcs[src] = true
+			lim.nsrc++
+		}
+	}
+	lim.lock.Unlock()
+
+	rejectsrcs.Add(src, 1)
+}
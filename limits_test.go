@@ -0,0 +1,191 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 09:40 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"expvar"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimits(t *testing.T) {
+
+	b := New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    "u13-r14.phlccs1.example.com",
+		Port:        1234,
+		Iface:       tIface{},
+		Limits:      Limits{MaxRecords: 20, MaxNewPerMin: 10, MaxNetInfo: 2, MaxFieldLen: 64},
+	})
+
+	rec := func(id string, nnet int) PeerImport {
+		pi := tRecord(b, id, STATUS_UP)
+		for i := 0; i < nnet; i++ {
+			pi.NetInfo = append(pi.NetInfo, &NetInfo{Addr: fmt.Sprintf("10.0.0.%d:1234", i)})
+		}
+		return &tPeer{pi}
+	}
+
+	n := tRejects("netinfo")
+	b.updateFrom("mrtesty@src", []PeerImport{rec("mrtesty@many", 3), rec("mrtesty@"+strings.Repeat("x", 100), 1)})
+
+	if b.Get("mrtesty@many") != nil || tRejects("netinfo") != n+1 {
+		t.Fatalf("size limits not enforced")
+	}
+
+	// too many new peers from one source
+	var recs []PeerImport
+	for i := 0; i < 30; i++ {
+		recs = append(recs, rec(fmt.Sprintf("mrtesty@fake%d", i), 1))
+	}
+	b.updateFrom("mrtesty@hostile", recs)
+
+	nfake := 0
+	b.ForAllData(func(id string, isup bool, d interface{}) {
+		if strings.HasPrefix(id, "mrtesty@fake") {
+			nfake++
+		}
+	})
+	if nfake != 10 {
+		t.Fatalf("accepted %d new peers", nfake)
+	}
+	if rejectsrcs.Get("mrtesty@hostile") == nil {
+		t.Fatalf("source not recorded")
+	}
+
+	// other sources are not affected
+	b.updateFrom("mrtesty@friendly", []PeerImport{rec("mrtesty@new", 1)})
+	if b.Get("mrtesty@new") == nil {
+		t.Fatalf("limited wrong source")
+	}
+
+	// unattributed reports share one allowance
+	for i := 0; i < 30; i++ {
+		b.Update(rec(fmt.Sprintf("mrtesty@anon%d", i), 1))
+	}
+	nanon := 0
+	b.ForAllData(func(id string, isup bool, d interface{}) {
+		if strings.HasPrefix(id, "mrtesty@anon") {
+			nanon++
+		}
+	})
+	if nanon != 10 {
+		t.Fatalf("accepted %d unattributed new peers", nanon)
+	}
+
+	// or contacting us directly
+	for i := 0; i < 30; i++ {
+		b.UpdateSceptical(rec(fmt.Sprintf("mrtesty@scept%d", i), 1))
+	}
+	b.lock.RLock()
+	nscept := len(b.skeptical)
+	b.lock.RUnlock()
+
+	if nscept != 10 {
+		t.Fatalf("accepted %d new sceptical peers", nscept)
+	}
+}
+
+func TestSizeLimits(t *testing.T) {
+
+	b := New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    "u13-r14.phlccs1.example.com",
+		Port:        1234,
+		Iface:       tIface{},
+		Limits:      Limits{MaxMetadata: 2, MaxBroadcast: 2, MaxPayload: 16, MaxVia: 3},
+	})
+
+	bcast := func(n int, size int) []*Broadcast {
+		var res []*Broadcast
+		for i := 0; i < n; i++ {
+			res = append(res, &Broadcast{
+				Id:         fmt.Sprintf("mrtesty@bcast/%d", i),
+				Name:       "test",
+				Payload:    make([]byte, size),
+				TimeExpire: b.ClockNow() + uint64(time.Minute),
+			})
+		}
+		return res
+	}
+
+	tests := []struct {
+		reason string
+		fix    func(*PeerInfo)
+	}{
+		{"metadata", func(pi *PeerInfo) { pi.Metadata = map[string]string{"a": "1", "b": "2", "c": "3"} }},
+		{"broadcast", func(pi *PeerInfo) { pi.Broadcast = bcast(3, 1) }},
+		{"payload", func(pi *PeerInfo) { pi.Broadcast = bcast(1, 17) }},
+		{"via", func(pi *PeerInfo) { pi.ViaPath = []string{"x", "y", "z", "w"}; pi.Hops = 4 }},
+		{"hops", func(pi *PeerInfo) { pi.ViaPath = []string{"x", "y"}; pi.Hops = 1 }},
+	}
+
+	for i, test := range tests {
+		id := fmt.Sprintf("mrtesty@big%d", i)
+		pi := tRecord(b, id, STATUS_UP, "10.0.0.1:1234")
+		test.fix(pi)
+
+		n := tRejects(test.reason)
+		b.updateFrom("mrtesty@src", []PeerImport{&tPeer{pi}})
+
+		if b.Get(id) != nil || tRejects(test.reason) != n+1 {
+			t.Fatalf("%s limit not enforced", test.reason)
+		}
+	}
+
+	// within the limits
+	pi := tRecord(b, "mrtesty@ok", STATUS_UP, "10.0.0.1:1234")
+	pi.Metadata = map[string]string{"a": "1", "b": "2"}
+	pi.Broadcast = bcast(2, 16)
+	pi.ViaPath = []string{"x", "y"}
+	pi.Hops = 2
+	b.updateFrom("mrtesty@src", []PeerImport{&tPeer{pi}})
+
+	if b.Get("mrtesty@ok") == nil {
+		t.Fatalf("rejected a valid record")
+	}
+
+	// from an older version, relayed by older versions
+	pi = tRecord(b, "mrtesty@old", STATUS_UP, "10.0.0.1:1234")
+	pi.Via = ". mrtesty@x mrtesty@y"
+	b.updateFrom("mrtesty@src", []PeerImport{&tPeer{pi}})
+
+	if b.Get("mrtesty@old") == nil {
+		t.Fatalf("rejected an older version's record")
+	}
+
+	// the path may be as long as we allow hops
+	c := New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    "u14-r14.phlccs1.example.com",
+		Port:        1234,
+		Iface:       tIface{},
+		MaxHops:     32,
+	})
+
+	pi = tRecord(c, "mrtesty@far", STATUS_UP, "10.0.0.1:1234")
+	for i := 0; i < 20; i++ {
+		pi.ViaPath = append(pi.ViaPath, fmt.Sprintf("mrtesty@hop%d", i))
+	}
+	pi.Hops = 20
+	c.updateFrom("mrtesty@src", []PeerImport{&tPeer{pi}})
+
+	if c.Get("mrtesty@far") == nil {
+		t.Fatalf("rejected a long path")
+	}
+}
+
+func tRejects(k string) int64 {
+	if v, ok := rejects.Get(k).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
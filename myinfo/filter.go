@@ -0,0 +1,148 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 16:35 (EDT)
+// Function: which interfaces + addresses to use
+
+package myinfo
+
+import (
+	"net"
+	"path/filepath"
+)
+
+type netFilter struct {
+	intfs   []string
+	exintfs []string
+	include []*net.IPNet
+	exclude []*net.IPNet
+	private []*net.IPNet
+	natmap  NatMap
+}
+
+func (c *NetConf) filter() (*netFilter, error) {
+
+	var err error
+
+	f := &netFilter{
+		intfs:   c.Interfaces,
+		exintfs: c.ExcludeInterfaces,
+	}
+
+	// check the patterns
+	for _, pat := range append(append([]string{}, f.intfs...), f.exintfs...) {
+		if _, err = filepath.Match(pat, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.include, err = parseNets(c.Include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = parseNets(c.Exclude); err != nil {
+		return nil, err
+	}
+
+	pvt := c.Private
+	if len(pvt) == 0 {
+		pvt = pvtRange[:]
+	}
+	if f.private, err = parseNets(pvt); err != nil {
+		return nil, err
+	}
+
+	if f.natmap, err = ParseNatMap(c.NatMap); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Check reports any problems with the config
+func (c *NetConf) Check() error {
+	_, err := c.filter()
+	return err
+}
+
+// Filtered reports whether the config limits which interfaces or addresses are used
+func (c *NetConf) Filtered() bool {
+	return len(c.Interfaces) != 0 || len(c.ExcludeInterfaces) != 0 || len(c.Include) != 0 || len(c.Exclude) != 0
+}
+
+// Unfiltered returns a copy of the config without the interface and address filters
+func (c *NetConf) Unfiltered() *NetConf {
+
+	u := *c
+	u.Interfaces = nil
+	u.ExcludeInterfaces = nil
+	u.Include = nil
+	u.Exclude = nil
+
+	return &u
+}
+
+func (f *netFilter) intfOK(name string) bool {
+
+	if len(f.intfs) != 0 && !matchAny(f.intfs, name) {
+		return false
+	}
+	if matchAny(f.exintfs, name) {
+		return false
+	}
+	return true
+}
+
+func (f *netFilter) addrOK(ip net.IP) bool {
+
+	if len(f.include) != 0 && !inNets(f.include, ip) {
+		return false
+	}
+	if inNets(f.exclude, ip) {
+		return false
+	}
+	return true
+}
+
+func (f *netFilter) isPrivate(ip net.IP) bool {
+	return inNets(f.private, ip)
+}
+
+// ################################################################
+
+func parseNets(cidrs []string) ([]*net.IPNet, error) {
+
+	var nets []*net.IPNet
+
+	for _, c := range cidrs {
+		_, block, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, block)
+	}
+
+	return nets, nil
+}
+
+func inNets(nets []*net.IPNet, ip net.IP) bool {
+
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(pats []string, name string) bool {
+
+	for _, pat := range pats {
+		if ok, _ := filepath.Match(pat, name); ok {
+			return true
+		}
+	}
+	return false
+}
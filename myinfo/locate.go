@@ -0,0 +1,215 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 14:52 (EDT)
+// Function: where am I?
+
+package myinfo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	LOCATIONFILE = "/etc/location"
+	HTTPTIMEOUT  = 2 * time.Second
+)
+
+var ErrNoLocation = errors.New("location not found")
+
+type Location struct {
+	Datacenter string
+	Rack       string
+}
+
+type Locator interface {
+	Locate() (*Location, error)
+}
+
+// read from environment variables
+type EnvLocator struct {
+	Datacenter string // variable names, default DATACENTER, RACK
+	Rack       string
+}
+
+// read key=value lines from a file
+type FileLocator struct {
+	Path       string // default /etc/location
+	Datacenter string // key names, default datacenter, rack
+	Rack       string
+}
+
+// fetch from an instance metadata http endpoint
+// the response body is the value. if it looks like a path, the last component is used.
+type HTTPLocator struct {
+	Datacenter string // urls
+	Rack       string
+	Header     map[string]string // eg. Metadata-Flavor: Google
+	Timeout    time.Duration
+}
+
+// try each in turn. each field is taken from the first locator that has it
+type Chain []Locator
+
+// ################################################################
+
+func (l *EnvLocator) Locate() (*Location, error) {
+
+	loc := &Location{
+		Datacenter: os.Getenv(orDefault(l.Datacenter, "DATACENTER")),
+		Rack:       os.Getenv(orDefault(l.Rack, "RACK")),
+	}
+
+	return loc.check()
+}
+
+func (l *FileLocator) Locate() (*Location, error) {
+
+	path := orDefault(l.Path, LOCATIONFILE)
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dckey := orDefault(l.Datacenter, "datacenter")
+	rkkey := orDefault(l.Rack, "rack")
+	loc := &Location{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(file))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k := strings.TrimSpace(kv[0])
+		v := strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+
+		switch k {
+		case dckey:
+			loc.Datacenter = v
+		case rkkey:
+			loc.Rack = v
+		}
+	}
+
+	return loc.check()
+}
+
+func (l *HTTPLocator) Locate() (*Location, error) {
+
+	client := &http.Client{Timeout: l.Timeout}
+	if client.Timeout == 0 {
+		client.Timeout = HTTPTIMEOUT
+	}
+
+	loc := &Location{}
+	var err error
+
+	if l.Datacenter != "" {
+		loc.Datacenter, err = l.get(client, l.Datacenter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if l.Rack != "" {
+		loc.Rack, err = l.get(client, l.Rack)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return loc.check()
+}
+
+func (l *HTTPLocator) get(client *http.Client, url string) (string, error) {
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range l.Header {
+		req.Header.Set(k, v)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return "", fmt.Errorf("metadata server replied %s", res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// "projects/1234/zones/us-east1-b" => "us-east1-b"
+	v := strings.TrimSpace(string(body))
+	if i := strings.LastIndex(v, "/"); i != -1 {
+		v = v[i+1:]
+	}
+
+	return v, nil
+}
+
+func (c Chain) Locate() (*Location, error) {
+
+	loc := &Location{}
+	err := ErrNoLocation
+
+	for _, l := range c {
+		if loc.Datacenter != "" && loc.Rack != "" {
+			break
+		}
+
+		ll, lerr := l.Locate()
+		if lerr != nil {
+			err = lerr
+			continue
+		}
+
+		if loc.Datacenter == "" {
+			loc.Datacenter = ll.Datacenter
+		}
+		if loc.Rack == "" {
+			loc.Rack = ll.Rack
+		}
+	}
+
+	if loc.Datacenter == "" && loc.Rack == "" {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// ################################################################
+
+func (loc *Location) check() (*Location, error) {
+
+	if loc.Datacenter == "" && loc.Rack == "" {
+		return nil, ErrNoLocation
+	}
+	return loc, nil
+}
+
+func orDefault(s string, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 15:20 (EDT)
+// Function:
+
+package myinfo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestEnvLocator(t *testing.T) {
+
+	os.Setenv("TEST_KIBITZ_DC", "sjc1")
+	defer os.Unsetenv("TEST_KIBITZ_DC")
+
+	loc, err := (&EnvLocator{Datacenter: "TEST_KIBITZ_DC", Rack: "TEST_KIBITZ_RACK"}).Locate()
+
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	shouldBe(t, "sjc1", loc.Datacenter)
+	shouldBe(t, "", loc.Rack)
+}
+
+func TestFileLocator(t *testing.T) {
+
+	f, _ := ioutil.TempFile("", "location")
+	defer os.Remove(f.Name())
+
+	f.WriteString("# where am i?\ndatacenter = phl1\nrack=\"r14\"\nother=x\n")
+	f.Close()
+
+	loc, err := (&FileLocator{Path: f.Name()}).Locate()
+
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	shouldBe(t, "phl1", loc.Datacenter)
+	shouldBe(t, "r14", loc.Rack)
+
+	_, err = (&FileLocator{Path: "/nonexistent/location"}).Locate()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestHTTPLocator(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(403)
+			return
+		}
+		switch r.URL.Path {
+		case "/zone":
+			w.Write([]byte("projects/1234/zones/us-east1-b\n"))
+		case "/rack":
+			w.Write([]byte("r7"))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer srv.Close()
+
+	loc, err := (&HTTPLocator{
+		Datacenter: srv.URL + "/zone",
+		Rack:       srv.URL + "/rack",
+		Header:     map[string]string{"Metadata-Flavor": "Google"},
+	}).Locate()
+
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	shouldBe(t, "us-east1-b", loc.Datacenter)
+	shouldBe(t, "r7", loc.Rack)
+
+	_, err = (&HTTPLocator{Datacenter: srv.URL + "/zone"}).Locate()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestChain(t *testing.T) {
+
+	os.Setenv("TEST_KIBITZ_RACK", "r3")
+	defer os.Unsetenv("TEST_KIBITZ_RACK")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("dc2"))
+	}))
+	defer srv.Close()
+
+	loc, err := Chain{
+		&FileLocator{Path: "/nonexistent/location"},
+		&EnvLocator{Datacenter: "TEST_KIBITZ_DC", Rack: "TEST_KIBITZ_RACK"},
+		&HTTPLocator{Datacenter: srv.URL, Rack: srv.URL},
+	}.Locate()
+
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	shouldBe(t, "dc2", loc.Datacenter)
+	shouldBe(t, "r3", loc.Rack)
+
+	_, err = Chain{&FileLocator{Path: "/nonexistent/location"}}.Locate()
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -22,12 +22,18 @@ type Info struct {
 	Hostname   string
 	Datacenter string
 	Rack       string
+	Zone       string
+	Host       string
 	Id         string
 	Net        []NetInfo
 	myaddrs    map[string]bool
 }
 
 func GetInfo(host string) *Info {
+	return GetInfoRules(host, nil)
+}
+
+func GetInfoRules(host string, rules Rules) *Info {
 
 	info := &Info{
 		myaddrs: make(map[string]bool),
@@ -39,8 +45,10 @@ func GetInfo(host string) *Info {
 	}
 	info.clean = normalizeName(info.Hostname)
 
-	info.learnDatacenter()
-	info.learnRack()
+	if !info.learnRules(rules) {
+		info.learnDatacenter()
+		info.learnRack()
+	}
 
 	return info
 }
@@ -98,7 +106,31 @@ func (i *Info) learnRack() string {
 	return i.Rack
 }
 
+type NetConf struct {
+	Dom               string // natdom for private addrs, usually the datacenter
+	Port              int
+	Interfaces        []string          // only use interfaces matching these globs (eg. "eth*")
+	ExcludeInterfaces []string          // do not use interfaces matching these globs (eg. "docker*")
+	Include           []string          // only use addrs in these cidrs
+	Exclude           []string          // do not use addrs in these cidrs
+	Private           []string          // private (nat) ranges. default: RFC 1918 + RFC 4193
+	NatMap            map[string]string // cidr => nat domain, overrides Dom
+}
+
 func Network(dom string, port int) []NetInfo {
+	ni, _ := NetworkConf(&NetConf{Dom: dom, Port: port})
+	return ni
+}
+
+func NetworkConf(c *NetConf) ([]NetInfo, error) {
+
+	f, err := c.filter()
+	if err != nil {
+		return nil, err
+	}
+
+	dom := c.Dom
+	port := c.Port
 
 	var ni []NetInfo
 	intfs, _ := net.Interfaces()
@@ -110,6 +142,9 @@ func Network(dom string, port int) []NetInfo {
 		if i.Flags&net.FlagUp == 0 {
 			continue
 		}
+		if !f.intfOK(i.Name) {
+			continue
+		}
 
 		addrs, _ := i.Addrs()
 
@@ -118,6 +153,9 @@ func Network(dom string, port int) []NetInfo {
 			if !ip.IsGlobalUnicast() {
 				continue
 			}
+			if !f.addrOK(ip) {
+				continue
+			}
 
 			var ipport string
 
@@ -128,9 +166,13 @@ func Network(dom string, port int) []NetInfo {
 			}
 
 			natdom := ""
-			if isPrivateIP(ip) {
+			if mdom, ok := f.natmap.lookupIP(ip); ok {
+				// explicitly configured
+				natdom = mdom
+			} else if f.isPrivate(ip) {
 				// use datacenter name or netblock
 				// QQQ - use concatenation dc+blk - will people reuse the same net in multiple dcs?
+				// (if so, configure a NatMap)
 				natdom = dom
 
 				if natdom == "" {
@@ -142,7 +184,7 @@ func Network(dom string, port int) []NetInfo {
 		}
 	}
 
-	return ni
+	return ni, nil
 }
 
 func (i *Info) IsOwnAddr(addr string) bool {
@@ -153,19 +195,3 @@ var pvtRange = [...]string{
 	"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", // RFC 1918
 	"fc00::/7", // RFC 4193
 }
-
-func isPrivateIP(ip net.IP) bool {
-
-	if ip == nil {
-		return false
-	}
-
-	for _, n := range pvtRange {
-		_, block, _ := net.ParseCIDR(n)
-		if block.Contains(ip) {
-			return true
-		}
-	}
-	return false
-
-}
@@ -6,6 +6,7 @@
 package myinfo
 
 import (
+	"net"
 	"testing"
 )
 
@@ -26,6 +27,162 @@ func TestRack(t *testing.T) {
 	shouldBe(t, "", tNew("foo.sjc1.domain.com").learnRack())
 }
 
+func TestRules(t *testing.T) {
+
+	rules, err := ParseRules([]string{
+		// AWS
+		`^(?P<host>ip-[0-9-]+)\.(?P<dc>[a-z]+-[a-z]+-[0-9]+)\.compute\.internal$`,
+		// GCP
+		`^(?P<host>[^.]+)\.(?P<zone>(?P<dc>[a-z]+-[a-z]+[0-9]+)-[a-z])\.c\.[^.]+\.internal$`,
+		// host.rack.dc.domain
+		`^(?P<host>[^.]+)\.(?P<rack>rack[0-9]+)\.(?P<dc>[^.]+)\.`,
+		// dc-rack-host
+		`^(?P<dc>[a-z]+[0-9]+)-(?P<rack>r[0-9]+)-(?P<host>[^.]+)$`,
+	})
+
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	tests := []struct {
+		name, dc, rack, zone, host string
+	}{
+		{"ip-10-1-2-3.us-west-2.compute.internal", "us-west-2", "", "", "ip-10-1-2-3"},
+		{"gke-prod-pool-1-abcd.us-central1-a.c.myproject.internal", "us-central1", "", "us-central1-a", "gke-prod-pool-1-abcd"},
+		{"web01.rack12.nyc3.example.com", "nyc3", "rack12", "", "web01"},
+		{"nyc3-r12-web01", "nyc3", "r12", "", "web01"},
+		// no rule matches, use the default
+		{"u12-r14.phlccs1.example.com", "phlccs1", "r14", "", ""},
+	}
+
+	for _, c := range tests {
+		i := GetInfoRules(c.name, rules)
+		shouldBe(t, c.dc, i.Datacenter)
+		shouldBe(t, c.rack, i.Rack)
+		shouldBe(t, c.zone, i.Zone)
+		shouldBe(t, c.host, i.Host)
+	}
+
+	_, err = ParseRules([]string{`^([^.]+)\.`})
+	if err == nil {
+		t.Errorf("expected error, no named groups")
+	}
+	_, err = ParseRules([]string{`^(?P<dc>[^.]+`})
+	if err == nil {
+		t.Errorf("expected error, invalid regexp")
+	}
+}
+
+func TestFilter(t *testing.T) {
+
+	f, err := (&NetConf{
+		Interfaces:        []string{"eth*", "en*"},
+		ExcludeInterfaces: []string{"eth9"},
+		Exclude:           []string{"10.9.0.0/16"},
+		Private:           []string{"10.0.0.0/8", "100.64.0.0/10"},
+	}).filter()
+
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	intfs := []struct {
+		name string
+		ok   bool
+	}{
+		{"eth0", true}, {"en1", true}, {"eth9", false}, {"docker0", false}, {"br-1234", false},
+	}
+	for _, c := range intfs {
+		if f.intfOK(c.name) != c.ok {
+			t.Errorf("intf %s: expected %v", c.name, c.ok)
+		}
+	}
+
+	addrs := []struct {
+		addr string
+		ok   bool
+		pvt  bool
+	}{
+		{"10.1.2.3", true, true},
+		{"10.9.2.3", false, true},
+		{"100.100.1.1", true, true},
+		{"192.168.1.1", true, false},
+		{"8.8.8.8", true, false},
+	}
+	for _, c := range addrs {
+		ip := net.ParseIP(c.addr)
+		if f.addrOK(ip) != c.ok {
+			t.Errorf("addr %s: expected ok %v", c.addr, c.ok)
+		}
+		if f.isPrivate(ip) != c.pvt {
+			t.Errorf("addr %s: expected private %v", c.addr, c.pvt)
+		}
+	}
+
+	// include only
+	f, _ = (&NetConf{Include: []string{"192.168.0.0/16"}}).filter()
+	if f.addrOK(net.ParseIP("10.1.2.3")) || !f.addrOK(net.ParseIP("192.168.1.1")) {
+		t.Errorf("include failed")
+	}
+	// default private ranges
+	if !f.isPrivate(net.ParseIP("172.16.1.1")) || !f.isPrivate(net.ParseIP("fd00::1")) {
+		t.Errorf("default private failed")
+	}
+
+	_, err = (&NetConf{Exclude: []string{"10.0.0.0"}}).filter()
+	if err == nil {
+		t.Errorf("expected error, invalid cidr")
+	}
+	_, err = (&NetConf{Interfaces: []string{"eth["}}).filter()
+	if err == nil {
+		t.Errorf("expected error, invalid glob")
+	}
+
+	c := &NetConf{Interfaces: []string{"eth*"}, Exclude: []string{"10.9.0.0/16"}, Private: []string{"10.0.0.0/8"}}
+	if !c.Filtered() || c.Unfiltered().Filtered() || len(c.Unfiltered().Private) != 1 {
+		t.Errorf("unfiltered failed")
+	}
+}
+
+func TestNatMap(t *testing.T) {
+
+	nm, err := ParseNatMap(map[string]string{
+		"10.0.0.0/8":    "corp",
+		"10.20.0.0/16":  "vpc-east",
+		"fd00:1::/32":   "vpc-east",
+		"100.64.0.0/10": "cgnat",
+	})
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	cases := []struct {
+		addr string
+		dom  string
+		ok   bool
+	}{
+		{"10.1.2.3:1234", "corp", true},
+		{"10.20.2.3:1234", "vpc-east", true},
+		{"10.20.2.3", "vpc-east", true},
+		{"[fd00:1::5]:1234", "vpc-east", true},
+		{"100.64.1.1:80", "cgnat", true},
+		{"192.168.1.1:1234", "", false},
+		{"bogus", "", false},
+	}
+	for _, c := range cases {
+		dom, ok := nm.Lookup(c.addr)
+		if ok != c.ok {
+			t.Errorf("%s: expected %v", c.addr, c.ok)
+		}
+		shouldBe(t, c.dom, dom)
+	}
+
+	_, err = ParseNatMap(map[string]string{"10.0.0.0": "x"})
+	if err == nil {
+		t.Errorf("expected error, invalid cidr")
+	}
+}
+
 func shouldBe(t *testing.T, a string, b string) {
 
 	if a != b {
@@ -0,0 +1,79 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 17:10 (EDT)
+// Function: explicit cidr => nat domain mapping
+
+package myinfo
+
+import (
+	"net"
+	"sort"
+)
+
+// private networks that can reach each other (eg. peered vpcs)
+// should be given the same nat domain name
+type NatMap []natEntry
+
+type natEntry struct {
+	net *net.IPNet
+	dom string
+}
+
+// cidr => nat domain name
+func ParseNatMap(m map[string]string) (NatMap, error) {
+
+	var nm NatMap
+
+	for cidr, dom := range m {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		nm = append(nm, natEntry{net: block, dom: dom})
+	}
+
+	// most specific first
+	sort.Slice(nm, func(i, j int) bool {
+		oi, _ := nm[i].net.Mask.Size()
+		oj, _ := nm[j].net.Mask.Size()
+		if oi != oj {
+			return oi > oj
+		}
+		return nm[i].net.String() < nm[j].net.String()
+	})
+
+	return nm, nil
+}
+
+// Lookup returns the nat domain for an ip or "ip:port"
+func (nm NatMap) Lookup(addr string) (string, bool) {
+
+	if len(nm) == 0 {
+		return "", false
+	}
+
+	ip := parseAddr(addr)
+	if ip == nil {
+		return "", false
+	}
+
+	return nm.lookupIP(ip)
+}
+
+func (nm NatMap) lookupIP(ip net.IP) (string, bool) {
+
+	for _, e := range nm {
+		if e.net.Contains(ip) {
+			return e.dom, true
+		}
+	}
+	return "", false
+}
+
+func parseAddr(addr string) net.IP {
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(addr)
+}
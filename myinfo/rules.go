@@ -0,0 +1,79 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 15:48 (EDT)
+// Function: configurable hostname parsing
+
+package myinfo
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// regular expressions with named groups: dc, rack, zone, host
+// tried in order, the first one that matches is used
+type Rules []*regexp.Regexp
+
+var ruleGroups = map[string]bool{"dc": true, "rack": true, "zone": true, "host": true}
+
+func ParseRules(pats []string) (Rules, error) {
+
+	var rules Rules
+
+	for _, pat := range pats {
+		re, err := ParseRule(pat)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, re)
+	}
+
+	return rules, nil
+}
+
+func ParseRule(pat string) (*regexp.Regexp, error) {
+
+	re, err := regexp.Compile(pat)
+	if err != nil {
+		return nil, fmt.Errorf("host rule '%s': %v", pat, err)
+	}
+
+	for _, g := range re.SubexpNames() {
+		if ruleGroups[g] {
+			return re, nil
+		}
+	}
+
+	return nil, fmt.Errorf("host rule '%s' has no dc, rack, zone, or host group", pat)
+}
+
+// apply the first matching rule. returns true if one matched
+func (i *Info) learnRules(rules Rules) bool {
+
+	for _, re := range rules {
+		m := re.FindStringSubmatch(i.clean)
+		if m == nil {
+			continue
+		}
+
+		for n, g := range re.SubexpNames() {
+			if m[n] == "" {
+				continue
+			}
+			switch g {
+			case "dc":
+				i.Datacenter = m[n]
+			case "rack":
+				i.Rack = m[n]
+			case "zone":
+				i.Zone = m[n]
+			case "host":
+				i.Host = m[n]
+			}
+		}
+		return true
+	}
+
+	return false
+}
@@ -16,7 +16,18 @@ var dlme = diag.Logger("kibitz_myself")
 
 func (pdb *DB) learn(c *Conf) {
 
-	myself := myinfo.GetInfo(pdb.host)
+	// skip (and complain about) any bad ones, use the rest
+	var rules myinfo.Rules
+	for _, pat := range c.HostRules {
+		re, err := myinfo.ParseRule(pat)
+		if err != nil {
+			dlme.Problem("invalid host rule: %v", err)
+			continue
+		}
+		rules = append(rules, re)
+	}
+
+	myself := myinfo.GetInfoRules(pdb.host, rules)
 
 	if pdb.id == "" {
 		pdb.id = myself.ServerId(pdb.sys, pdb.env, pdb.port)
@@ -25,6 +36,22 @@ func (pdb *DB) learn(c *Conf) {
 	if pdb.host == "" {
 		pdb.host = myself.Hostname
 	}
+
+	if c.Locator != nil && (pdb.dc == "" || pdb.rack == "") {
+		loc, err := c.Locator.Locate()
+		if err != nil {
+			dlme.Verbose("cannot determine location: %v", err)
+		} else {
+			if pdb.dc == "" {
+				pdb.dc = loc.Datacenter
+			}
+			if pdb.rack == "" {
+				pdb.rack = loc.Rack
+			}
+		}
+	}
+
+	// otherwise, from the hostname
 	if pdb.dc == "" {
 		pdb.dc = myself.Datacenter
 	}
@@ -41,25 +68,212 @@ func (pdb *DB) learn(c *Conf) {
 
 func (pdb *DB) learnNetwork() {
 
-	ninfo := myinfo.Network(pdb.dc, pdb.port)
+	netinfo := pdb.discoverNetwork()
+
+	pdb.selflock.Lock()
+	defer pdb.selflock.Unlock()
+
+	pdb.discovered = netinfo
+	pdb.setNetInfo(pdb.advertised(netinfo))
+}
+
+func (pdb *DB) discoverNetwork() []*NetInfo {
+
+	var netinfo []*NetInfo
+
+	nc := pdb.netconf
+	nc.Dom = pdb.Datacenter()
+	nc.Port = pdb.port
+
+	ninfo, err := myinfo.NetworkConf(&nc)
+	if err != nil {
+		dlme.Problem("invalid network config: %v", err)
+	}
+	if len(ninfo) == 0 && nc.Filtered() {
+		// better to be reachable on an unexpected network than not at all
+		dlme.Problem("network filters match no addresses, using all of them")
+		ninfo, _ = myinfo.NetworkConf(nc.Unfiltered())
+	}
 
 	for _, ni := range ninfo {
-		pdb.myaddrs[ni.Addr] = ni.Dom
-		pdb.mydoms[ni.Dom] = true
-		pdb.nmon.Add(ni.Dom)
+		dlme.Debug("intf %s [%s]", ni.Addr, ni.Dom)
+
+		netinfo = append(netinfo, &NetInfo{
+			Addr:   ni.Addr,
+			Natdom: ni.Dom,
+		})
+	}
+
+	return netinfo
+}
+
+// Rescan looks for changes to our network interfaces
+// returns true if anything changed
+func (pdb *DB) Rescan() bool {
+
+	netinfo := pdb.discoverNetwork()
+
+	pdb.selflock.Lock()
+
+	if sameNetInfo(netinfo, pdb.discovered) {
+		pdb.selflock.Unlock()
+		return false
+	}
+
+	pdb.discovered = netinfo
+	pdb.setNetInfo(pdb.advertised(netinfo))
+	pdb.confChanged()
+	pdb.selflock.Unlock()
+
+	dlme.Verbose("network interfaces changed")
+
+	// tell everyone
+	pdb.kickNow()
+	return true
+}
+
+func sameNetInfo(a []*NetInfo, b []*NetInfo) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	doms := make(map[string]string)
+	for _, ni := range a {
+		doms[ni.GetAddr()] = ni.GetNatdom()
+	}
+	for _, ni := range b {
+		dom, ok := doms[ni.GetAddr()]
+		if !ok || dom != ni.GetNatdom() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// the addresses we tell peers about
+func (pdb *DB) advertised(discovered []*NetInfo) []*NetInfo {
+
+	if len(pdb.advertise) == 0 {
+		return discovered
+	}
+
+	var netinfo []*NetInfo
+
+	if !pdb.advOnly {
+		netinfo = append(netinfo, discovered...)
+	}
+	for _, ni := range pdb.advertise {
+		dlme.Debug("advertise %s [%s]", ni.Addr, ni.Natdom)
+		netinfo = append(netinfo, ni)
+	}
+
+	return netinfo
+}
+
+// caller must hold selflock
+func (pdb *DB) setNetInfo(netinfo []*NetInfo) {
+
+	pdb.netinfo = netinfo
+	pdb.myaddrs = make(map[string]string)
+	pdb.mydoms = make(map[string]bool)
+	pdb.bestaddr = ""
+
+	// our own addrs include the ones we are bound to, even if not advertised
+	for _, ni := range pdb.discovered {
+		pdb.myaddrs[ni.Addr] = ni.Natdom
+		pdb.mydoms[ni.Natdom] = true
+		pdb.nmon.Add(ni.Natdom)
+	}
+
+	for _, ni := range netinfo {
+		pdb.myaddrs[ni.Addr] = ni.Natdom
+		pdb.mydoms[ni.Natdom] = true
+		pdb.nmon.Add(ni.Natdom)
 		pdb.bestaddr = ni.Addr
+	}
+}
 
-		dlme.Debug("intf %s [%s]", ni.Addr, ni.Dom)
+// ################################################################
+
+type SelfConfig struct {
+	Hostname      string
+	Datacenter    string
+	Rack          string
+	Advertise     []*NetInfo // as in Conf. kept when the interfaces are rescanned
+	AdvertiseOnly bool
+	Metadata      map[string]string
+}
 
-		a := ni.Addr
-		d := ni.Dom
+// UpdateSelf changes what we advertise about ourself.
+// peers will see it as a config change.
+func (pdb *DB) UpdateSelf(fnc func(*SelfConfig)) {
 
-		pdb.netinfo = append(pdb.netinfo, &NetInfo{
-			Addr:   a,
-			Natdom: d,
-		})
+	pdb.updlock.Lock()
+	defer pdb.updlock.Unlock()
+
+	pdb.selflock.RLock()
+	c := &SelfConfig{
+		Hostname:      pdb.host,
+		Datacenter:    pdb.dc,
+		Rack:          pdb.rack,
+		Advertise:     append([]*NetInfo{}, pdb.advertise...),
+		AdvertiseOnly: pdb.advOnly,
+		Metadata:      copyMetadata(pdb.metadata),
+	}
+	pdb.selflock.RUnlock()
+
+	fnc(c)
 
+	pdb.selflock.Lock()
+	pdb.host = c.Hostname
+	pdb.dc = c.Datacenter
+	pdb.rack = c.Rack
+	pdb.metadata = c.Metadata
+	pdb.advertise = c.Advertise
+	pdb.advOnly = c.AdvertiseOnly
+	pdb.setNetInfo(pdb.advertised(pdb.discovered))
+	pdb.confChanged()
+	pdb.selflock.Unlock()
+
+	dlme.Debug("config changed")
+
+	// tell everyone
+	pdb.kickNow()
+}
+
+// caller must hold selflock
+func (pdb *DB) confChanged() {
+	pdb.timeConf = pdb.clock.Inc().Uint64()
+	pdb.myconfs[pdb.timeConf] = true
+	pdb.rumors.add(pdb.id)
+}
+
+func (pdb *DB) isMyConf(t uint64) bool {
+	pdb.selflock.RLock()
+	defer pdb.selflock.RUnlock()
+	return pdb.myconfs[t]
+}
+
+func (pdb *DB) kickNow() {
+	select {
+	case pdb.kick <- struct{}{}:
+	default:
+	}
+}
+
+func copyMetadata(md map[string]string) map[string]string {
+
+	if md == nil {
+		return nil
 	}
+
+	c := make(map[string]string, len(md))
+	for k, v := range md {
+		c[k] = v
+	}
+	return c
 }
 
 func (pdb *DB) Myself() PeerImport {
@@ -67,10 +281,21 @@ func (pdb *DB) Myself() PeerImport {
 	return pdb.iface.Myself(info)
 }
 
+// myself, with any pending broadcasts attached.
+// only for records that are actually being sent - each call counts as a transmission
+func (pdb *DB) myselfToSend() PeerImport {
+	info := pdb.MyInfo()
+	info.Broadcast = pdb.bcast.outgoing(info.GetTimeCreated())
+	return pdb.iface.Myself(info)
+}
+
 func (pdb *DB) MyInfo() *PeerInfo {
 
 	now := pdb.clock.Inc().Uint64()
 
+	pdb.selflock.RLock()
+	defer pdb.selflock.RUnlock()
+
 	r := &PeerInfo{
 		Subsystem:   pdb.sys,
 		Environment: pdb.env,
@@ -81,11 +306,14 @@ func (pdb *DB) MyInfo() *PeerInfo {
 		TimeChecked: now,
 		TimeLastUp:  now,
 		TimeUpSince: pdb.bootTime,
-		TimeConf:    pdb.bootTime,
+		TimeConf:    pdb.timeConf,
 		Via:         viaDot,
+		Fingerprint: pdb.Fingerprint(),
+		Metadata:    pdb.metadata,
 	}
 
 	r.SetStatusCode(STATUS_UP)
+	r.Health = int32(pdb.Health())
 
 	if pdb.dc != "" {
 		r.Datacenter = pdb.dc
@@ -98,6 +326,9 @@ func (pdb *DB) MyInfo() *PeerInfo {
 }
 
 func (pdb *DB) IsOwnAddr(addr string) bool {
+	pdb.selflock.RLock()
+	defer pdb.selflock.RUnlock()
+
 	_, ok := pdb.myaddrs[addr]
 	return ok
 }
@@ -0,0 +1,131 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 14:20 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"testing"
+	"time"
+)
+
+type tEvIface struct {
+	tIface
+	ev chan *Event
+}
+
+func (t tEvIface) Event(e *Event) { t.ev <- e }
+
+func TestUpdateSelf(t *testing.T) {
+
+	a := tNewDB("u12-r14.phlccs1.example.com", 1234)
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	evs := make(chan *Event, 10)
+	b.iface = tEvIface{ev: evs}
+
+	b.Update(a.Myself())
+
+	a.UpdateSelf(func(c *SelfConfig) {
+		c.Rack = "r15"
+		c.Metadata = map[string]string{"version": "42"}
+	})
+
+	b.Update(a.Myself())
+
+	select {
+	case e := <-evs:
+		if e.Type != EVENT_CONFIG_CHANGED || e.Before.Rack != "r14" || e.After.Rack != "r15" {
+			t.Fatalf("event %v %+v", e.Type, e.After)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no event")
+	}
+
+	pe := b.Get(a.Id()).GetExport()
+	if pe.Rack != "r15" || pe.Metadata["version"] != "42" {
+		t.Fatalf("export %+v", pe)
+	}
+
+	// addresses survive an interface change
+	a.UpdateSelf(func(c *SelfConfig) {
+		c.Advertise = []*NetInfo{{Addr: "203.0.113.5:8080"}}
+		c.AdvertiseOnly = true
+	})
+
+	a.selflock.Lock()
+	a.discovered = []*NetInfo{{Addr: "192.0.2.1:1234"}}
+	a.selflock.Unlock()
+	a.Rescan()
+
+	if ni := a.MyInfo().GetNetInfo(); len(ni) != 1 || ni[0].GetAddr() != "203.0.113.5:8080" {
+		t.Fatalf("netinfo %v", ni)
+	}
+}
+
+func TestAdvertise(t *testing.T) {
+
+	pdb := New(&Conf{
+		System:        "mrtesty",
+		Environment:   "test",
+		Hostname:      "u12-r14.phlccs1.example.com",
+		Port:          1234,
+		Iface:         tIface{},
+		Advertise:     []*NetInfo{{Addr: "203.0.113.5:8080"}},
+		AdvertiseOnly: true,
+	})
+
+	ni := pdb.MyInfo().GetNetInfo()
+
+	if len(ni) != 1 || ni[0].GetAddr() != "203.0.113.5:8080" {
+		t.Fatalf("netinfo %v", ni)
+	}
+
+	if !pdb.IsOwnAddr("203.0.113.5:8080") {
+		t.Fatalf("advertised addr is not own")
+	}
+	for _, d := range pdb.discovered {
+		if !pdb.IsOwnAddr(d.GetAddr()) {
+			t.Fatalf("bound addr %s is not own", d.GetAddr())
+		}
+	}
+}
+
+func TestHostRules(t *testing.T) {
+
+	pdb := New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    "web01.rack12.nyc3.example.com",
+		Port:        1234,
+		Iface:       tIface{},
+		HostRules:   []string{`^(?P<dc>[^.]+`, `^[^.]+\.(?P<rack>[^.]+)\.(?P<dc>[^.]+)\.`},
+	})
+
+	// the bad one is skipped, not the rest
+	if pdb.Datacenter() != "nyc3" || pdb.Rack() != "rack12" {
+		t.Fatalf("dc %s rack %s", pdb.Datacenter(), pdb.Rack())
+	}
+}
+
+func TestNetFilterFallback(t *testing.T) {
+
+	all := tNewDB("u12-r14.phlccs1.example.com", 1234).MyInfo().GetNetInfo()
+
+	for _, intfs := range [][]string{{"nonesuch*"}, {"eth["}} {
+		pdb := New(&Conf{
+			System:      "mrtesty",
+			Environment: "test",
+			Hostname:    "u12-r14.phlccs1.example.com",
+			Port:        1234,
+			Iface:       tIface{},
+			Interfaces:  intfs,
+		})
+
+		// still reachable
+		if !sameNetInfo(pdb.MyInfo().GetNetInfo(), all) {
+			t.Fatalf("%v: netinfo %v", intfs, pdb.MyInfo().GetNetInfo())
+		}
+	}
+}
@@ -6,6 +6,7 @@
 package kibitz
 
 import (
+	"sort"
 	"sync"
 	"time"
 )
@@ -15,11 +16,19 @@ const STALE = int64(2 * time.Minute)
 type netMon struct {
 	lock   sync.RWMutex
 	lastUp map[string]int64
+	isUp   map[string]bool // as last reported
+}
+
+type NetStatus struct {
+	Name   string // nat domain, or "public"
+	IsUp   bool
+	LastUp time.Time
 }
 
 func netMonNew() *netMon {
 	return &netMon{
 		lastUp: make(map[string]int64),
+		isUp:   make(map[string]bool),
 	}
 }
 
@@ -29,7 +38,11 @@ func (nm *netMon) Add(net string) {
 
 	nm.lock.Lock()
 	defer nm.lock.Unlock()
-	nm.lastUp[net] = now()
+
+	if _, ok := nm.lastUp[net]; !ok {
+		nm.lastUp[net] = now()
+		nm.isUp[net] = true
+	}
 }
 
 func (nm *netMon) SetUp(net string) {
@@ -57,6 +70,53 @@ func (nm *netMon) IsUp(net string) (bool, bool) {
 	return false, false
 }
 
+// Status returns the state of all of the networks, sorted by name
+func (nm *netMon) Status() []NetStatus {
+
+	nm.lock.RLock()
+	defer nm.lock.RUnlock()
+
+	var res []NetStatus
+	limit := now() - STALE
+
+	for net, t := range nm.lastUp {
+		res = append(res, NetStatus{
+			Name:   net,
+			IsUp:   t >= limit,
+			LastUp: time.Unix(0, t),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// changes returns the networks that went up or stale since the last call
+func (nm *netMon) changes() []NetStatus {
+
+	nm.lock.Lock()
+	defer nm.lock.Unlock()
+
+	var res []NetStatus
+	limit := now() - STALE
+
+	for net, t := range nm.lastUp {
+		up := t >= limit
+		if up == nm.isUp[net] {
+			continue
+		}
+
+		nm.isUp[net] = up
+		res = append(res, NetStatus{
+			Name:   net,
+			IsUp:   up,
+			LastUp: time.Unix(0, t),
+		})
+	}
+
+	return res
+}
+
 func netName(n string) string {
 	if n == "" {
 		return "public"
@@ -0,0 +1,44 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 17:40 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"testing"
+)
+
+func TestNetMon(t *testing.T) {
+
+	nm := netMonNew()
+	nm.Add("")
+	nm.Add("phl1")
+
+	if len(nm.changes()) != 0 {
+		t.Fatalf("new networks should start up")
+	}
+
+	st := nm.Status()
+	if len(st) != 2 || st[0].Name != "phl1" || st[1].Name != "public" || !st[0].IsUp {
+		t.Fatalf("status %+v", st)
+	}
+
+	// pretend we haven't heard from phl1 in a while
+	nm.lastUp["phl1"] = now() - 2*STALE
+
+	ch := nm.changes()
+	if len(ch) != 1 || ch[0].Name != "phl1" || ch[0].IsUp {
+		t.Fatalf("expected phl1 stale, got %+v", ch)
+	}
+	if len(nm.changes()) != 0 {
+		t.Fatalf("change reported twice")
+	}
+
+	nm.SetUp("phl1")
+
+	ch = nm.changes()
+	if len(ch) != 1 || ch[0].Name != "phl1" || !ch[0].IsUp {
+		t.Fatalf("expected phl1 up, got %+v", ch)
+	}
+}
@@ -6,6 +6,7 @@
 package kibitz
 
 import (
+	"math/rand"
 	"sync"
 	"time"
 
@@ -24,8 +25,11 @@ const (
 )
 
 const (
-	MAXFAIL = 3
-	MAXVIA  = 1024
+	MAXFAIL    = 3
+	MAXHOPS    = 16
+	MAXVIA     = 1024            // legacy via string
+	BACKOFF    = 5 * time.Second // after a failure, wait this long to retry. doubling each time
+	MAXBACKOFF = 2 * time.Minute
 )
 
 type PeerImport interface {
@@ -40,7 +44,12 @@ type Peer struct {
 	status   PeerStatus
 	numFail  int
 	lastTry  time.Time
+	nextTry  time.Time // backoff while down
 	bestAddr string
+	sticky   string // address that last worked
+	addrs    map[string]*AddrHealth
+	conflict time.Time // last reported id conflict
+	fpart    uint64    // our part of the fingerprint
 	info     *PeerInfo
 	data     PeerImport
 }
@@ -55,10 +64,17 @@ type Export struct {
 	Rack        string
 	Datacenter  string
 	BestAddr    string
+	Addrs       []AddrHealth
+	Via         []string
+	Hops        int
+	Metadata    map[string]string
 	TimeLastUp  uint64
 	TimeUpSince uint64
 	LastTry     time.Time
+	NextTry     time.Time
 	IsUp        bool
+	IsDegraded  bool
+	IsDraining  bool
 	IsSameRack  bool
 	IsSameDC    bool
 }
@@ -85,6 +101,10 @@ func (p *Peer) Update(px PeerImport, pdb *DB) {
 	defer p.lock.Unlock()
 
 	switch {
+	case pi == p.info:
+		// the record the peer was discovered with
+		break
+
 	case pi.GetTimeCreated() <= p.info.GetTimeCreated():
 		// discard old outdated update
 		return
@@ -98,9 +118,25 @@ func (p *Peer) Update(px PeerImport, pdb *DB) {
 		return
 	}
 
+	p.checkConflict(pi)
+
 	// did config change?
 	changed := pi.GetTimeConf() > p.info.GetTimeConf()
 
+	if changed {
+		pdb.event(&Event{
+			Type:   EVENT_CONFIG_CHANGED,
+			Id:     p.id,
+			Before: pdb.exportInfo(p.info),
+			After:  pdb.exportInfo(pi),
+		})
+	}
+
+	// or health?
+	if pi.GetHealth() != p.info.GetHealth() {
+		changed = true
+	}
+
 	bestaddr := p.figureBestAddr(pi)
 	if bestaddr != p.bestAddr {
 		p.bestAddr = bestaddr
@@ -109,7 +145,17 @@ func (p *Peer) Update(px PeerImport, pdb *DB) {
 
 	p.info = pi
 	p.data = px
+	p.pruneAddrs()
 
+	// add ourself to the path
+	path := pi.GetViaPath()
+	if len(path) >= pdb.maxhops {
+		path = path[len(path)-pdb.maxhops+1:]
+	}
+	pi.ViaPath = append(append([]string{}, path...), pdb.id)
+	pi.Hops++
+
+	// and for older versions
 	via := pi.GetVia() + " " + pdb.id
 	if len(via) > MAXVIA {
 		via = via[:MAXVIA]
@@ -131,6 +177,7 @@ func (p *Peer) SetIsUp(now lamport.Time) {
 
 	p.numFail = 0
 	p.lastTry = time.Now()
+	p.nextTry = time.Time{}
 
 	t := now.Uint64()
 	p.info.TimeLastUp = t
@@ -156,6 +203,12 @@ func (p *Peer) SetMaybeDn(now lamport.Time) {
 	p.info.TimeUpSince = t
 
 	if p.numFail > MAXFAIL || p.status == STATUS_DOWN {
+		// down. do not keep wasting time on it
+		ndown := p.numFail - MAXFAIL
+		if ndown < 1 {
+			ndown = 1
+		}
+		p.nextTry = p.lastTry.Add(backoff(ndown))
 		p.changeStatus(STATUS_DOWN, false)
 		return
 	}
@@ -163,6 +216,18 @@ func (p *Peer) SetMaybeDn(now lamport.Time) {
 	p.changeStatus(STATUS_MAYBEDN, false)
 }
 
+// exponential, with jitter
+func backoff(nfail int) time.Duration {
+
+	d := MAXBACKOFF
+	if nfail < 16 && BACKOFF<<uint(nfail-1) < MAXBACKOFF {
+		d = BACKOFF << uint(nfail-1)
+	}
+
+	// somewhere in [d/2, d)
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
+
 func (p *Peer) Kill() {
 
 	p.lock.Lock()
@@ -183,8 +248,11 @@ func (p *Peer) changeStatus(st PeerStatus, changed bool) bool {
 		p.info.SetStatusCode(st)
 	}
 
+	p.refingerprint()
+
 	if os != st {
 		dl.Debug("peer %s changed to %s", p.id, st)
+		p.pdb.churned()
 	}
 
 	if os == st && !changed {
@@ -202,10 +270,27 @@ func (p *Peer) changeStatus(st PeerStatus, changed bool) bool {
 
 func (p *Peer) figureBestAddr(pi *PeerInfo) string {
 
+	if p.sticky != "" && hasAddr(pi, p.sticky) {
+		// the one that last worked
+		return p.sticky
+	}
+
+	if best := p.bestOf(pi, true); best != "" {
+		return best
+	}
+	return p.bestOf(pi, false)
+}
+
+func (p *Peer) bestOf(pi *PeerInfo, skipFailing bool) string {
+
 	var best string
 
 	for _, ni := range pi.NetInfo {
-		dom := ni.GetNatdom()
+		if skipFailing && p.failing(ni.GetAddr()) {
+			continue
+		}
+
+		dom := p.pdb.natdomOf(ni)
 
 		if dom != "" {
 			if p.pdb.DomOK(dom) {
@@ -241,42 +326,73 @@ func (p *Peer) GetExport() *Export {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	pi := p.info
+	return p.export()
+}
+
+// caller must hold lock
+func (p *Peer) export() *Export {
+
+	e := p.pdb.exportInfo(p.info)
+	e.Id = p.id
+	e.Status = p.status
+	e.BestAddr = p.bestAddr
+	e.Addrs = p.exportAddrs()
+	e.LastTry = p.lastTry
+	e.NextTry = p.nextTry
+
+	return e
+}
+
+// export a peer info record
+func (pdb *DB) exportInfo(pi *PeerInfo) *Export {
+
+	st := PeerStatus(pi.GetStatusCode())
+	health := Health(pi.GetHealth())
 
 	return &Export{
-		Id:          p.id,
-		Status:      p.status,
+		Id:          pi.GetServerId(),
+		Status:      st,
 		Netinfo:     pi.GetNetInfo(),
 		Sys:         pi.GetSubsystem(),
 		Hostname:    pi.GetHostname(),
 		Env:         pi.GetEnvironment(),
 		Rack:        pi.GetRack(),
 		Datacenter:  pi.GetDatacenter(),
-		IsUp:        (pi.GetStatusCode() == int32(STATUS_UP)),
-		BestAddr:    p.bestAddr,
+		IsUp:        isAvailable(st, health),
+		IsDegraded:  (health == HEALTH_DEGRADED),
+		IsDraining:  (health == HEALTH_DRAINING),
+		Via:         pi.GetViaPath(),
+		Hops:        int(pi.GetHops()),
+		Metadata:    pi.GetMetadata(),
 		TimeLastUp:  pi.GetTimeLastUp(),
 		TimeUpSince: pi.GetTimeUpSince(),
-		LastTry:     p.lastTry,
-		IsSameRack:  (pi.GetRack() == p.pdb.rack),
-		IsSameDC:    (pi.GetDatacenter() == p.pdb.dc),
+		IsSameRack:  (pi.GetRack() == pdb.Rack()),
+		IsSameDC:    (pi.GetDatacenter() == pdb.Datacenter()),
 	}
 }
 
 func (pdb *DB) GetExportSelf() *Export {
 
 	now := pdb.clock.Inc().Uint64()
+	health := pdb.Health()
+
+	pdb.selflock.RLock()
+	defer pdb.selflock.RUnlock()
 
 	return &Export{
 		Id:          pdb.id,
 		Status:      STATUS_UP,
+		IsDegraded:  (health == HEALTH_DEGRADED),
+		IsDraining:  (health == HEALTH_DRAINING),
 		Env:         pdb.env,
 		Sys:         pdb.sys,
 		Netinfo:     pdb.netinfo,
 		Hostname:    pdb.host,
 		Rack:        pdb.rack,
 		Datacenter:  pdb.dc,
-		IsUp:        true,
+		IsUp:        isAvailable(STATUS_UP, health),
 		BestAddr:    pdb.bestaddr,
+		Metadata:    pdb.metadata,
 		TimeLastUp:  now,
 		TimeUpSince: now,
 		IsSameRack:  true,
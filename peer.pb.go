@@ -86,16 +86,23 @@ type PeerInfo struct {
 	Datacenter  string `protobuf:"bytes,6,opt,name=datacenter,proto3" json:"datacenter,omitempty"`
 	Rack        string `protobuf:"bytes,7,opt,name=rack,proto3" json:"rack,omitempty"`
 	// lamport clocks (see lamport.go)
-	TimeChecked          uint64     `protobuf:"varint,8,opt,name=time_checked,json=timeChecked,proto3" json:"time_checked,omitempty"`
-	TimeLastUp           uint64     `protobuf:"varint,9,opt,name=time_last_up,json=timeLastUp,proto3" json:"time_last_up,omitempty"`
-	TimeCreated          uint64     `protobuf:"varint,10,opt,name=time_created,json=timeCreated,proto3" json:"time_created,omitempty"`
-	TimeConf             uint64     `protobuf:"varint,11,opt,name=time_conf,json=timeConf,proto3" json:"time_conf,omitempty"`
-	TimeUpSince          uint64     `protobuf:"varint,12,opt,name=time_up_since,json=timeUpSince,proto3" json:"time_up_since,omitempty"`
-	Via                  string     `protobuf:"bytes,17,opt,name=via,proto3" json:"via,omitempty"`
-	NetInfo              []*NetInfo `protobuf:"bytes,20,rep,name=net_info,json=netInfo,proto3" json:"net_info,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	TimeChecked          uint64            `protobuf:"varint,8,opt,name=time_checked,json=timeChecked,proto3" json:"time_checked,omitempty"`
+	TimeLastUp           uint64            `protobuf:"varint,9,opt,name=time_last_up,json=timeLastUp,proto3" json:"time_last_up,omitempty"`
+	TimeCreated          uint64            `protobuf:"varint,10,opt,name=time_created,json=timeCreated,proto3" json:"time_created,omitempty"`
+	TimeConf             uint64            `protobuf:"varint,11,opt,name=time_conf,json=timeConf,proto3" json:"time_conf,omitempty"`
+	TimeUpSince          uint64            `protobuf:"varint,12,opt,name=time_up_since,json=timeUpSince,proto3" json:"time_up_since,omitempty"`
+	Via                  string            `protobuf:"bytes,17,opt,name=via,proto3" json:"via,omitempty"`
+	Hops                 uint32            `protobuf:"varint,18,opt,name=hops,proto3" json:"hops,omitempty"`
+	NetInfo              []*NetInfo        `protobuf:"bytes,20,rep,name=net_info,json=netInfo,proto3" json:"net_info,omitempty"`
+	Broadcast            []*Broadcast      `protobuf:"bytes,21,rep,name=broadcast,proto3" json:"broadcast,omitempty"`
+	Fingerprint          uint64            `protobuf:"varint,22,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,23,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	EvictedBy            string            `protobuf:"bytes,24,opt,name=evicted_by,json=evictedBy,proto3" json:"evicted_by,omitempty"`
+	Health               int32             `protobuf:"varint,25,opt,name=health,proto3" json:"health,omitempty"`
+	ViaPath              []string          `protobuf:"bytes,26,rep,name=via_path,json=viaPath,proto3" json:"via_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *PeerInfo) Reset()         { *m = PeerInfo{} }
@@ -222,6 +229,13 @@ func (m *PeerInfo) GetVia() string {
 	return ""
 }
 
+func (m *PeerInfo) GetHops() uint32 {
+	if m != nil {
+		return m.Hops
+	}
+	return 0
+}
+
 func (m *PeerInfo) GetNetInfo() []*NetInfo {
 	if m != nil {
 		return m.NetInfo
@@ -229,38 +243,291 @@ func (m *PeerInfo) GetNetInfo() []*NetInfo {
 	return nil
 }
 
+func (m *PeerInfo) GetBroadcast() []*Broadcast {
+	if m != nil {
+		return m.Broadcast
+	}
+	return nil
+}
+
+func (m *PeerInfo) GetFingerprint() uint64 {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return 0
+}
+
+func (m *PeerInfo) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *PeerInfo) GetEvictedBy() string {
+	if m != nil {
+		return m.EvictedBy
+	}
+	return ""
+}
+
+func (m *PeerInfo) GetHealth() int32 {
+	if m != nil {
+		return m.Health
+	}
+	return 0
+}
+
+func (m *PeerInfo) GetViaPath() []string {
+	if m != nil {
+		return m.ViaPath
+	}
+	return nil
+}
+
+type Broadcast struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Origin               string   `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Payload              []byte   `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	TimeExpire           uint64   `protobuf:"varint,5,opt,name=time_expire,json=timeExpire,proto3" json:"time_expire,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Broadcast) Reset()         { *m = Broadcast{} }
+func (m *Broadcast) String() string { return proto.CompactTextString(m) }
+func (*Broadcast) ProtoMessage()    {}
+func (*Broadcast) Descriptor() ([]byte, []int) {
+	return fileDescriptor_055ae5a865fc1c9e, []int{2}
+}
+func (m *Broadcast) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Broadcast) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Broadcast.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Broadcast) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Broadcast.Merge(m, src)
+}
+func (m *Broadcast) XXX_Size() int {
+	return m.Size()
+}
+func (m *Broadcast) XXX_DiscardUnknown() {
+	xxx_messageInfo_Broadcast.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Broadcast proto.InternalMessageInfo
+
+func (m *Broadcast) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Broadcast) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+func (m *Broadcast) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Broadcast) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Broadcast) GetTimeExpire() uint64 {
+	if m != nil {
+		return m.TimeExpire
+	}
+	return 0
+}
+
+// for delta exchanges (see delta.go)
+type DigestEntry struct {
+	ServerId             string   `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	TimeCreated          uint64   `protobuf:"varint,2,opt,name=time_created,json=timeCreated,proto3" json:"time_created,omitempty"`
+	TimeChecked          uint64   `protobuf:"varint,3,opt,name=time_checked,json=timeChecked,proto3" json:"time_checked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DigestEntry) Reset()         { *m = DigestEntry{} }
+func (m *DigestEntry) String() string { return proto.CompactTextString(m) }
+func (*DigestEntry) ProtoMessage()    {}
+func (*DigestEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_055ae5a865fc1c9e, []int{3}
+}
+func (m *DigestEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DigestEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DigestEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DigestEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DigestEntry.Merge(m, src)
+}
+func (m *DigestEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *DigestEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_DigestEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DigestEntry proto.InternalMessageInfo
+
+func (m *DigestEntry) GetServerId() string {
+	if m != nil {
+		return m.ServerId
+	}
+	return ""
+}
+
+func (m *DigestEntry) GetTimeCreated() uint64 {
+	if m != nil {
+		return m.TimeCreated
+	}
+	return 0
+}
+
+func (m *DigestEntry) GetTimeChecked() uint64 {
+	if m != nil {
+		return m.TimeChecked
+	}
+	return 0
+}
+
+type Digest struct {
+	Entry                []*DigestEntry `protobuf:"bytes,1,rep,name=entry,proto3" json:"entry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Digest) Reset()         { *m = Digest{} }
+func (m *Digest) String() string { return proto.CompactTextString(m) }
+func (*Digest) ProtoMessage()    {}
+func (*Digest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_055ae5a865fc1c9e, []int{4}
+}
+func (m *Digest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Digest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Digest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Digest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Digest.Merge(m, src)
+}
+func (m *Digest) XXX_Size() int {
+	return m.Size()
+}
+func (m *Digest) XXX_DiscardUnknown() {
+	xxx_messageInfo_Digest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Digest proto.InternalMessageInfo
+
+func (m *Digest) GetEntry() []*DigestEntry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*NetInfo)(nil), "kibitz.NetInfo")
 	proto.RegisterType((*PeerInfo)(nil), "kibitz.PeerInfo")
+	proto.RegisterMapType((map[string]string)(nil), "kibitz.PeerInfo.MetadataEntry")
+	proto.RegisterType((*Broadcast)(nil), "kibitz.Broadcast")
+	proto.RegisterType((*DigestEntry)(nil), "kibitz.DigestEntry")
+	proto.RegisterType((*Digest)(nil), "kibitz.Digest")
 }
 
 func init() { proto.RegisterFile("peer.proto", fileDescriptor_055ae5a865fc1c9e) }
 
 var fileDescriptor_055ae5a865fc1c9e = []byte{
-	// 368 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x92, 0xc1, 0xea, 0xd3, 0x40,
-	0x10, 0xc6, 0x8d, 0x69, 0xd3, 0x64, 0x52, 0xb1, 0x2e, 0x22, 0x8b, 0x4a, 0x8c, 0x3d, 0x15, 0x0f,
-	0x3d, 0x28, 0xbe, 0x80, 0x3d, 0x15, 0x44, 0x24, 0xd2, 0x73, 0xd8, 0x66, 0x27, 0x74, 0xa9, 0xd9,
-	0x0d, 0xbb, 0x93, 0x82, 0x3e, 0x89, 0x6f, 0xe3, 0xd5, 0xa3, 0x8f, 0x20, 0xf5, 0x45, 0xfe, 0x64,
-	0xb7, 0xa5, 0xbd, 0x7d, 0xf3, 0xfb, 0x66, 0x3e, 0x76, 0x98, 0x05, 0xe8, 0x11, 0xed, 0xba, 0xb7,
-	0x86, 0x0c, 0x4b, 0x8e, 0x6a, 0xaf, 0xe8, 0xe7, 0xf2, 0x23, 0xcc, 0xbe, 0x20, 0x6d, 0x75, 0x6b,
-	0x18, 0x83, 0x89, 0x90, 0xd2, 0xf2, 0xa8, 0x8c, 0x56, 0x59, 0xe5, 0x35, 0x7b, 0x01, 0x89, 0x16,
-	0x24, 0x4d, 0xc7, 0x1f, 0x7b, 0x7a, 0xa9, 0x96, 0xbf, 0x63, 0x48, 0xbf, 0x22, 0x5a, 0x3f, 0xf8,
-	0x06, 0x72, 0x47, 0x82, 0x06, 0x57, 0x37, 0x46, 0xa2, 0x9f, 0x9f, 0x56, 0x10, 0xd0, 0xc6, 0x48,
-	0x64, 0xaf, 0x21, 0x73, 0xc3, 0xde, 0xfd, 0x70, 0x84, 0xd7, 0xa0, 0x1b, 0x60, 0x25, 0xe4, 0xa8,
-	0x4f, 0xca, 0x1a, 0xdd, 0xa1, 0x26, 0x1e, 0x7b, 0xff, 0x1e, 0xb1, 0x57, 0x90, 0x39, 0xb4, 0x27,
-	0xb4, 0xb5, 0x92, 0x7c, 0xe2, 0xfd, 0x34, 0x80, 0xad, 0x64, 0x2f, 0x21, 0x3d, 0x18, 0x47, 0x5a,
-	0x74, 0xc8, 0xa7, 0xc1, 0xbb, 0xd6, 0xac, 0x00, 0x90, 0x82, 0x44, 0x83, 0x9a, 0xd0, 0xf2, 0xc4,
-	0xbb, 0x77, 0x64, 0x5c, 0xd9, 0x8a, 0xe6, 0xc8, 0x67, 0x61, 0xe5, 0x51, 0xb3, 0xb7, 0x30, 0x27,
-	0xd5, 0x61, 0xdd, 0x1c, 0xb0, 0x39, 0xa2, 0xe4, 0x69, 0x19, 0xad, 0x26, 0x55, 0x3e, 0xb2, 0x4d,
-	0x40, 0xac, 0xbc, 0xb4, 0x7c, 0x17, 0x8e, 0xea, 0xa1, 0xe7, 0x99, 0x6f, 0x81, 0x91, 0x7d, 0x16,
-	0x8e, 0x76, 0xfd, 0x2d, 0xc4, 0xa2, 0x20, 0x94, 0x1c, 0xee, 0x42, 0x02, 0x1a, 0x97, 0x0a, 0x2d,
-	0x46, 0xb7, 0x3c, 0xf7, 0x7e, 0xea, 0x7d, 0xa3, 0x5b, 0xb6, 0x84, 0x27, 0xde, 0x1c, 0xfa, 0xda,
-	0x29, 0xdd, 0x20, 0x9f, 0xdf, 0x02, 0x76, 0xfd, 0xb7, 0x11, 0xb1, 0x05, 0xc4, 0x27, 0x25, 0xf8,
-	0x33, 0xff, 0xf6, 0x51, 0xb2, 0x77, 0x90, 0x6a, 0xa4, 0x5a, 0xe9, 0xd6, 0xf0, 0xe7, 0x65, 0xbc,
-	0xca, 0xdf, 0x3f, 0x5d, 0x87, 0x3b, 0xaf, 0x2f, 0x47, 0xae, 0x66, 0x3a, 0x88, 0x4f, 0x8b, 0x3f,
-	0xe7, 0x22, 0xfa, 0x7b, 0x2e, 0xa2, 0x7f, 0xe7, 0x22, 0xfa, 0xf5, 0xbf, 0x78, 0xb4, 0x4f, 0xfc,
-	0xcf, 0xf8, 0xf0, 0x10, 0x00, 0x00, 0xff, 0xff, 0xc8, 0x83, 0x26, 0x07, 0x27, 0x02, 0x00, 0x00,
+	// 640 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x54, 0xc1, 0x6e, 0xdb, 0x38,
+	0x10, 0x5d, 0xd9, 0xb1, 0x2d, 0x8d, 0x93, 0xdd, 0x84, 0x9b, 0xcd, 0x32, 0x69, 0xeb, 0xaa, 0x3e,
+	0xb9, 0x3d, 0xb8, 0x40, 0x83, 0x02, 0x45, 0x7a, 0x4b, 0x9a, 0x43, 0x80, 0xb6, 0x08, 0x54, 0xe4,
+	0x2c, 0xd0, 0xe2, 0x38, 0x22, 0x6c, 0x93, 0x02, 0x45, 0x0b, 0x75, 0x8f, 0xfd, 0x8a, 0x7e, 0x52,
+	0x8f, 0xfd, 0x84, 0x22, 0xfd, 0x82, 0xfe, 0x41, 0x41, 0x52, 0x8a, 0x9d, 0xe4, 0x36, 0xf3, 0xde,
+	0xd3, 0x70, 0xc8, 0x79, 0x23, 0x80, 0x02, 0x51, 0x8f, 0x0b, 0xad, 0x8c, 0x22, 0xdd, 0x99, 0x98,
+	0x08, 0xf3, 0x65, 0xf8, 0x1a, 0x7a, 0x1f, 0xd1, 0x5c, 0xc8, 0xa9, 0x22, 0x04, 0xb6, 0x18, 0xe7,
+	0x9a, 0x06, 0x71, 0x30, 0x8a, 0x12, 0x17, 0x93, 0x03, 0xe8, 0x4a, 0x66, 0xb8, 0x5a, 0xd0, 0x96,
+	0x43, 0xeb, 0x6c, 0xf8, 0xbb, 0x03, 0xe1, 0x25, 0xa2, 0x76, 0x1f, 0x3e, 0x85, 0x7e, 0x69, 0x98,
+	0x59, 0x96, 0x69, 0xa6, 0x38, 0xba, 0xef, 0x3b, 0x09, 0x78, 0xe8, 0x4c, 0x71, 0x24, 0x8f, 0x21,
+	0x2a, 0x97, 0x93, 0x72, 0x55, 0x1a, 0x6c, 0x0a, 0xad, 0x01, 0x12, 0x43, 0x1f, 0x65, 0x25, 0xb4,
+	0x92, 0x0b, 0x94, 0x86, 0xb6, 0x1d, 0xbf, 0x09, 0x91, 0x47, 0x10, 0x95, 0xa8, 0x2b, 0xd4, 0xa9,
+	0xe0, 0x74, 0xcb, 0xf1, 0xa1, 0x07, 0x2e, 0x38, 0x39, 0x82, 0x30, 0x57, 0xa5, 0x91, 0x6c, 0x81,
+	0xb4, 0xe3, 0xb9, 0x26, 0x27, 0x03, 0x00, 0xce, 0x0c, 0xcb, 0x50, 0x1a, 0xd4, 0xb4, 0xeb, 0xd8,
+	0x0d, 0xc4, 0x5e, 0x59, 0xb3, 0x6c, 0x46, 0x7b, 0xfe, 0xca, 0x36, 0x26, 0xcf, 0x60, 0xdb, 0x88,
+	0x05, 0xa6, 0x59, 0x8e, 0xd9, 0x0c, 0x39, 0x0d, 0xe3, 0x60, 0xb4, 0x95, 0xf4, 0x2d, 0x76, 0xe6,
+	0x21, 0x12, 0xd7, 0x92, 0x39, 0x2b, 0x4d, 0xba, 0x2c, 0x68, 0xe4, 0x24, 0x60, 0xb1, 0xf7, 0xac,
+	0x34, 0x57, 0xc5, 0xba, 0x88, 0x46, 0x66, 0x90, 0x53, 0xd8, 0x28, 0xe2, 0x21, 0x7b, 0x29, 0x2f,
+	0x51, 0x72, 0x4a, 0xfb, 0x8e, 0x0f, 0x1d, 0xaf, 0xe4, 0x94, 0x0c, 0x61, 0xc7, 0x91, 0xcb, 0x22,
+	0x2d, 0x85, 0xcc, 0x90, 0x6e, 0xaf, 0x0b, 0x5c, 0x15, 0x9f, 0x2c, 0x44, 0x76, 0xa1, 0x5d, 0x09,
+	0x46, 0xf7, 0x5c, 0xef, 0x36, 0xb4, 0xd7, 0xc9, 0x55, 0x51, 0x52, 0x12, 0x07, 0xa3, 0x9d, 0xc4,
+	0xc5, 0xe4, 0x05, 0x84, 0x12, 0x4d, 0x2a, 0xe4, 0x54, 0xd1, 0xfd, 0xb8, 0x3d, 0xea, 0xbf, 0xfa,
+	0x67, 0xec, 0x67, 0x3f, 0xae, 0x07, 0x9f, 0xf4, 0xa4, 0x0f, 0xc8, 0x4b, 0x88, 0x26, 0x5a, 0x31,
+	0x9e, 0xb1, 0xd2, 0xd0, 0xff, 0x9c, 0x78, 0xaf, 0x11, 0x9f, 0x36, 0x44, 0xb2, 0xd6, 0xd8, 0xd1,
+	0x4d, 0x85, 0xbc, 0x46, 0x5d, 0x68, 0x21, 0x0d, 0x3d, 0xf0, 0x4d, 0x6e, 0x40, 0xe4, 0x04, 0xc2,
+	0x05, 0x1a, 0x66, 0xdf, 0x9c, 0xfe, 0xef, 0x2a, 0x0e, 0x9a, 0x8a, 0x8d, 0x7f, 0xc6, 0x1f, 0x6a,
+	0xc1, 0xb9, 0x34, 0x7a, 0x95, 0xdc, 0xea, 0xc9, 0x13, 0x00, 0xac, 0x44, 0x66, 0x90, 0xa7, 0x93,
+	0x15, 0xa5, 0xde, 0x37, 0x35, 0x72, 0xba, 0xb2, 0xde, 0xcc, 0x91, 0xcd, 0x4d, 0x4e, 0x0f, 0x9d,
+	0xe3, 0xea, 0x8c, 0x1c, 0x42, 0x58, 0x09, 0x96, 0x16, 0xcc, 0xe4, 0xf4, 0x28, 0x6e, 0x8f, 0xa2,
+	0xa4, 0x57, 0x09, 0x76, 0xc9, 0x4c, 0x7e, 0xf4, 0x16, 0x76, 0xee, 0x1c, 0x66, 0xdf, 0x70, 0x86,
+	0xab, 0xda, 0xf2, 0x36, 0x24, 0xfb, 0xd0, 0xa9, 0xd8, 0x7c, 0x89, 0xb5, 0x4f, 0x7d, 0x72, 0xd2,
+	0x7a, 0x13, 0x0c, 0xbf, 0x06, 0x10, 0xdd, 0xbe, 0x02, 0xf9, 0x1b, 0x5a, 0x82, 0xd7, 0x1f, 0xb6,
+	0x04, 0xb7, 0xdd, 0x28, 0x2d, 0xae, 0x85, 0x6c, 0x36, 0xc5, 0x67, 0x76, 0x26, 0xce, 0x9a, 0xde,
+	0xd6, 0x2e, 0x26, 0x14, 0x7a, 0x05, 0x5b, 0xcd, 0x15, 0xf3, 0x6e, 0xde, 0x4e, 0x9a, 0xd4, 0xae,
+	0x92, 0x9b, 0x3b, 0x7e, 0x2e, 0x84, 0xf6, 0x7e, 0xae, 0x8d, 0x75, 0xee, 0x90, 0x61, 0x01, 0xfd,
+	0x77, 0xe2, 0x1a, 0x4b, 0xe3, 0xfb, 0xbf, 0xb3, 0x19, 0xc1, 0xbd, 0xcd, 0xb8, 0x6f, 0xc2, 0xd6,
+	0x43, 0x13, 0xde, 0x37, 0x7b, 0xfb, 0x81, 0xd9, 0x87, 0xc7, 0xd0, 0xf5, 0x27, 0x92, 0xe7, 0xd0,
+	0x41, 0x7b, 0x2a, 0x0d, 0xdc, 0x20, 0xff, 0x6d, 0x06, 0xb9, 0xd1, 0x50, 0xe2, 0x15, 0xa7, 0xbb,
+	0xdf, 0x6f, 0x06, 0xc1, 0x8f, 0x9b, 0x41, 0xf0, 0xf3, 0x66, 0x10, 0x7c, 0xfb, 0x35, 0xf8, 0x6b,
+	0xd2, 0x75, 0xff, 0x9d, 0xe3, 0x3f, 0x03, 0x00, 0x6d, 0x83, 0x79, 0x4e, 0x85, 0x04, 0x00, 0x00,
 }
 
 func (m *NetInfo) Marshal() (dAtA []byte, err error) {
@@ -328,6 +595,77 @@ func (m *PeerInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.ViaPath) > 0 {
+		for iNdEx := len(m.ViaPath) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ViaPath[iNdEx])
+			copy(dAtA[i:], m.ViaPath[iNdEx])
+			i = encodeVarintPeer(dAtA, i, uint64(len(m.ViaPath[iNdEx])))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xd2
+		}
+	}
+	if m.Health != 0 {
+		i = encodeVarintPeer(dAtA, i, uint64(m.Health))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc8
+	}
+	if len(m.EvictedBy) > 0 {
+		i -= len(m.EvictedBy)
+		copy(dAtA[i:], m.EvictedBy)
+		i = encodeVarintPeer(dAtA, i, uint64(len(m.EvictedBy)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc2
+	}
+	if len(m.Metadata) > 0 {
+		for k := range m.Metadata {
+			v := m.Metadata[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintPeer(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintPeer(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintPeer(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xba
+		}
+	}
+	if m.Fingerprint != 0 {
+		i = encodeVarintPeer(dAtA, i, uint64(m.Fingerprint))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb0
+	}
+	if len(m.Broadcast) > 0 {
+		for iNdEx := len(m.Broadcast) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Broadcast[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPeer(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0xaa
+		}
+	}
 	if len(m.NetInfo) > 0 {
 		for iNdEx := len(m.NetInfo) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -344,6 +682,13 @@ func (m *PeerInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			dAtA[i] = 0xa2
 		}
 	}
+	if m.Hops != 0 {
+		i = encodeVarintPeer(dAtA, i, uint64(m.Hops))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x90
+	}
 	if len(m.Via) > 0 {
 		i -= len(m.Via)
 		copy(dAtA[i:], m.Via)
@@ -428,57 +773,202 @@ func (m *PeerInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintPeer(dAtA []byte, offset int, v uint64) int {
-	offset -= sovPeer(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *Broadcast) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *NetInfo) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *Broadcast) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Broadcast) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Addr)
-	if l > 0 {
-		n += 1 + l + sovPeer(uint64(l))
-	}
-	l = len(m.Natdom)
-	if l > 0 {
-		n += 1 + l + sovPeer(uint64(l))
-	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
-}
-
-func (m *PeerInfo) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.TimeExpire != 0 {
+		i = encodeVarintPeer(dAtA, i, uint64(m.TimeExpire))
+		i--
+		dAtA[i] = 0x28
 	}
-	var l int
-	_ = l
-	if m.StatusCode != 0 {
-		n += 1 + sovPeer(uint64(m.StatusCode))
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintPeer(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0x22
 	}
-	l = len(m.Subsystem)
-	if l > 0 {
-		n += 1 + l + sovPeer(uint64(l))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintPeer(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	l = len(m.Environment)
-	if l > 0 {
-		n += 1 + l + sovPeer(uint64(l))
+	if len(m.Origin) > 0 {
+		i -= len(m.Origin)
+		copy(dAtA[i:], m.Origin)
+		i = encodeVarintPeer(dAtA, i, uint64(len(m.Origin)))
+		i--
+		dAtA[i] = 0x12
 	}
-	l = len(m.ServerId)
-	if l > 0 {
-		n += 1 + l + sovPeer(uint64(l))
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintPeer(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DigestEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DigestEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DigestEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.TimeChecked != 0 {
+		i = encodeVarintPeer(dAtA, i, uint64(m.TimeChecked))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.TimeCreated != 0 {
+		i = encodeVarintPeer(dAtA, i, uint64(m.TimeCreated))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ServerId) > 0 {
+		i -= len(m.ServerId)
+		copy(dAtA[i:], m.ServerId)
+		i = encodeVarintPeer(dAtA, i, uint64(len(m.ServerId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Digest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Digest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Digest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Entry) > 0 {
+		for iNdEx := len(m.Entry) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Entry[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPeer(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintPeer(dAtA []byte, offset int, v uint64) int {
+	offset -= sovPeer(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *NetInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Addr)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	l = len(m.Natdom)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *PeerInfo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.StatusCode != 0 {
+		n += 1 + sovPeer(uint64(m.StatusCode))
+	}
+	l = len(m.Subsystem)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	l = len(m.Environment)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	l = len(m.ServerId)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
 	}
 	l = len(m.Hostname)
 	if l > 0 {
@@ -511,12 +1001,116 @@ func (m *PeerInfo) Size() (n int) {
 	if l > 0 {
 		n += 2 + l + sovPeer(uint64(l))
 	}
+	if m.Hops != 0 {
+		n += 2 + sovPeer(uint64(m.Hops))
+	}
 	if len(m.NetInfo) > 0 {
 		for _, e := range m.NetInfo {
 			l = e.Size()
 			n += 2 + l + sovPeer(uint64(l))
 		}
 	}
+	if len(m.Broadcast) > 0 {
+		for _, e := range m.Broadcast {
+			l = e.Size()
+			n += 2 + l + sovPeer(uint64(l))
+		}
+	}
+	if m.Fingerprint != 0 {
+		n += 2 + sovPeer(uint64(m.Fingerprint))
+	}
+	if len(m.Metadata) > 0 {
+		for k, v := range m.Metadata {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovPeer(uint64(len(k))) + 1 + len(v) + sovPeer(uint64(len(v)))
+			n += mapEntrySize + 2 + sovPeer(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.EvictedBy)
+	if l > 0 {
+		n += 2 + l + sovPeer(uint64(l))
+	}
+	if m.Health != 0 {
+		n += 2 + sovPeer(uint64(m.Health))
+	}
+	if len(m.ViaPath) > 0 {
+		for _, s := range m.ViaPath {
+			l = len(s)
+			n += 2 + l + sovPeer(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Broadcast) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	l = len(m.Origin)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	if m.TimeExpire != 0 {
+		n += 1 + sovPeer(uint64(m.TimeExpire))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DigestEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ServerId)
+	if l > 0 {
+		n += 1 + l + sovPeer(uint64(l))
+	}
+	if m.TimeCreated != 0 {
+		n += 1 + sovPeer(uint64(m.TimeCreated))
+	}
+	if m.TimeChecked != 0 {
+		n += 1 + sovPeer(uint64(m.TimeChecked))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Digest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Entry) > 0 {
+		for _, e := range m.Entry {
+			l = e.Size()
+			n += 1 + l + sovPeer(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -588,11 +1182,716 @@ func (m *NetInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Addr = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
+			m.Addr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Natdom", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Natdom = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPeer(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PeerInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPeer
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PeerInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PeerInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
+			}
+			m.StatusCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StatusCode |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Subsystem", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Subsystem = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Environment", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Environment = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServerId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServerId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hostname", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hostname = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Datacenter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Datacenter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rack", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rack = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeChecked", wireType)
+			}
+			m.TimeChecked = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeChecked |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeLastUp", wireType)
+			}
+			m.TimeLastUp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeLastUp |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeCreated", wireType)
+			}
+			m.TimeCreated = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeCreated |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeConf", wireType)
+			}
+			m.TimeConf = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeConf |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeUpSince", wireType)
+			}
+			m.TimeUpSince = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeUpSince |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Via", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Via = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hops", wireType)
+			}
+			m.Hops = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Hops |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetInfo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NetInfo = append(m.NetInfo, &NetInfo{})
+			if err := m.NetInfo[len(m.NetInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Broadcast", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Broadcast = append(m.Broadcast, &Broadcast{})
+			if err := m.Broadcast[len(m.Broadcast)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fingerprint", wireType)
+			}
+			m.Fingerprint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Fingerprint |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPeer
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPeer
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthPeer
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthPeer
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPeer
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthPeer
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthPeer
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPeer(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthPeer
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Metadata[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 24:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EvictedBy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPeer
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPeer
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EvictedBy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 25:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Health", wireType)
+			}
+			m.Health = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPeer
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Health |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 26:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Natdom", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ViaPath", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -620,7 +1919,7 @@ func (m *NetInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Natdom = string(dAtA[iNdEx:postIndex])
+			m.ViaPath = append(m.ViaPath, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -644,7 +1943,7 @@ func (m *NetInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *PeerInfo) Unmarshal(dAtA []byte) error {
+func (m *Broadcast) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -667,34 +1966,15 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: PeerInfo: wiretype end group for non-group")
+			return fmt.Errorf("proto: Broadcast: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: PeerInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Broadcast: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StatusCode", wireType)
-			}
-			m.StatusCode = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPeer
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.StatusCode |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Subsystem", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -722,11 +2002,11 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Subsystem = string(dAtA[iNdEx:postIndex])
+			m.Id = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Environment", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -754,11 +2034,11 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Environment = string(dAtA[iNdEx:postIndex])
+			m.Origin = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ServerId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -786,13 +2066,13 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ServerId = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Hostname", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPeer
@@ -802,29 +2082,31 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthPeer
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPeer
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Hostname = string(dAtA[iNdEx:postIndex])
+			m.Payload = append(m.Payload[:0], dAtA[iNdEx:postIndex]...)
+			if m.Payload == nil {
+				m.Payload = []byte{}
+			}
 			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Datacenter", wireType)
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeExpire", wireType)
 			}
-			var stringLen uint64
+			m.TimeExpire = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPeer
@@ -834,27 +2116,65 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.TimeExpire |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthPeer
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPeer(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthPeer
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Datacenter = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 7:
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DigestEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPeer
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DigestEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DigestEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rack", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ServerId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -882,47 +2202,9 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Rack = string(dAtA[iNdEx:postIndex])
+			m.ServerId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 8:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeChecked", wireType)
-			}
-			m.TimeChecked = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPeer
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TimeChecked |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 9:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeLastUp", wireType)
-			}
-			m.TimeLastUp = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPeer
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TimeLastUp |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 10:
+		case 2:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field TimeCreated", wireType)
 			}
@@ -941,30 +2223,11 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 11:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeConf", wireType)
-			}
-			m.TimeConf = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPeer
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.TimeConf |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 12:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TimeUpSince", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeChecked", wireType)
 			}
-			m.TimeUpSince = 0
+			m.TimeChecked = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPeer
@@ -974,46 +2237,65 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TimeUpSince |= uint64(b&0x7F) << shift
+				m.TimeChecked |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 17:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Via", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPeer
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPeer(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthPeer
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthPeer
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			if postIndex > l {
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Digest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPeer
+			}
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Via = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 20:
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Digest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Digest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NetInfo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Entry", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1040,8 +2322,8 @@ func (m *PeerInfo) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.NetInfo = append(m.NetInfo, &NetInfo{})
-			if err := m.NetInfo[len(m.NetInfo)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Entry = append(m.Entry, &DigestEntry{})
+			if err := m.Entry[len(m.Entry)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
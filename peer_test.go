@@ -6,10 +6,55 @@
 package kibitz
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 )
 
+type tPeer struct {
+	info *PeerInfo
+}
+
+func (t *tPeer) GetPeerInfo() *PeerInfo   { return t.info }
+func (t *tPeer) SetPeerInfo(pi *PeerInfo) { t.info = pi }
+
+type tIface struct{}
+
+func (tIface) Send(string, time.Duration, PeerImport) ([]PeerImport, error) { return nil, nil }
+func (tIface) Change(string, bool, bool)                                    {}
+func (tIface) Update(string, bool, bool)                                    {}
+func (tIface) Myself(pi *PeerInfo) PeerImport                               { return &tPeer{pi} }
+
+func tNewDB(host string, port int) *DB {
+	return New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    host,
+		Port:        port,
+		Iface:       tIface{},
+	})
+}
+
+// a record about peer id, as if just heard about
+func tRecord(pdb *DB, id string, st PeerStatus, addrs ...string) *PeerInfo {
+
+	now := pdb.ClockNow()
+	pi := &PeerInfo{
+		Subsystem:   "mrtesty",
+		Environment: "test",
+		ServerId:    id,
+		StatusCode:  int32(st),
+		TimeCreated: now,
+		TimeLastUp:  now,
+	}
+	for _, addr := range addrs {
+		pi.NetInfo = append(pi.NetInfo, &NetInfo{Addr: addr})
+	}
+
+	return pi
+}
+
 func TestPeer(t *testing.T) {
 
 	pdb := New(&Conf{
@@ -30,3 +75,233 @@ func TestPeer(t *testing.T) {
 		fmt.Printf("ok %#v\n", pdb)
 	}
 }
+
+func TestVia(t *testing.T) {
+
+	a := tNewDB("u12-r14.phlccs1.example.com", 1234)
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+	c := tNewDB("u14-r14.phlccs1.example.com", 1234)
+
+	b.Update(a.Myself())
+
+	b.ForAllData(func(id string, isup bool, d interface{}) {
+		c.Update(d.(PeerImport))
+	})
+
+	pe := c.Get(a.Id()).GetExport()
+
+	if pe.Hops != 2 || len(pe.Via) != 2 || pe.Via[0] != b.Id() || pe.Via[1] != c.Id() {
+		t.Fatalf("via %v, hops %d", pe.Via, pe.Hops)
+	}
+
+	// do not accept it back
+	pd := c.Get(a.Id()).GetData().(PeerImport)
+
+	if b.viaOK(pd.GetPeerInfo()) {
+		t.Fatalf("loop not detected")
+	}
+
+	// older versions still see the string
+	if via := pd.GetPeerInfo().GetVia(); via != ". "+b.Id()+" "+c.Id() {
+		t.Fatalf("legacy via %q", via)
+	}
+
+	// and can still talk to us
+	var pi PeerInfo
+	if err := json.Unmarshal([]byte(`{"server_id":"mrtesty@old","via":". mrtesty@x"}`), &pi); err != nil {
+		t.Fatalf("json error %v", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	rec := func(created uint64, checked uint64, host string) PeerImport {
+		pi := tRecord(b, "mrtesty@merge", STATUS_UP)
+		pi.Hostname = host
+		pi.TimeCreated = created
+		pi.TimeChecked = checked
+		pi.TimeLastUp = created
+		return &tPeer{pi}
+	}
+
+	now := b.ClockNow()
+	b.Update(rec(now, now, "first"))
+
+	// the discovery record is used
+	pe := b.Get("mrtesty@merge").GetExport()
+	if pe.Status != STATUS_UP || pe.Hostname != "first" || pe.Hops != 1 {
+		t.Fatalf("discovered %s %s %d", pe.Status, pe.Hostname, pe.Hops)
+	}
+
+	// older, or same age, is discarded
+	b.Update(rec(now-1, now+10, "older"))
+	b.Update(rec(now, now+10, "same"))
+
+	if h := b.Get("mrtesty@merge").GetExport().Hostname; h != "first" {
+		t.Fatalf("merged %s", h)
+	}
+
+	b.Update(rec(now+1, now+1, "newer"))
+
+	if h := b.Get("mrtesty@merge").GetExport().Hostname; h != "newer" {
+		t.Fatalf("merged %s", h)
+	}
+}
+
+func TestConflict(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	rec := func(conf uint64, addr string) PeerImport {
+		pi := tRecord(b, "mrtesty@dupe", STATUS_UP, addr)
+		pi.TimeConf = conf
+		return &tPeer{pi}
+	}
+
+	n := idconflicts.Value()
+
+	b.Update(rec(100, "10.0.0.1:1234"))
+	b.Update(rec(200, "10.0.0.2:1234"))
+
+	if idconflicts.Value() != n {
+		t.Fatalf("config change is not a conflict")
+	}
+
+	b.Update(rec(100, "10.0.0.1:1234"))
+
+	if idconflicts.Value() != n+1 {
+		t.Fatalf("conflict not detected")
+	}
+
+	// someone else is using our id
+	me := b.MyInfo()
+	me.TimeConf++
+	me.TimeCreated = b.ClockNow()
+	b.Update(&tPeer{me})
+
+	if !b.IdConflict() || idconflicts.Value() != n+2 {
+		t.Fatalf("self conflict not detected")
+	}
+
+	// checking is not reporting
+	if b.isOK(me) || idconflicts.Value() != n+2 {
+		t.Fatalf("isok has side effects")
+	}
+}
+
+func TestNatMap(t *testing.T) {
+
+	pdb := New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    "u12-r14.phlccs1.example.com",
+		Port:        1234,
+		Iface:       tIface{},
+		NatMap:      map[string]string{"10.20.0.0/16": "vpc-east"},
+		Advertise:   []*NetInfo{{Addr: "10.20.1.1:1234", Natdom: "vpc-east"}},
+	})
+
+	p := &Peer{pdb: pdb}
+	pi := &PeerInfo{
+		NetInfo: []*NetInfo{
+			{Addr: "203.0.113.5:1234"},
+			{Addr: "10.20.7.7:1234", Natdom: "nycccs1"}, // peer thinks it is private to its dc
+		},
+	}
+
+	if best := p.figureBestAddr(pi); best != "10.20.7.7:1234" {
+		t.Errorf("expected mapped private addr, got %s", best)
+	}
+	if dom := pdb.natdomOf(pi.NetInfo[1]); dom != "vpc-east" {
+		t.Errorf("expected vpc-east, got %s", dom)
+	}
+}
+
+func TestAddrHealth(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+	b.Update(&tPeer{tRecord(b, "mrtesty@multi", STATUS_UP, "203.0.113.5:1234", "198.51.100.5:1234")})
+
+	p := b.Get("mrtesty@multi")
+	if pe := p.GetExport(); pe.BestAddr != "203.0.113.5:1234" {
+		t.Fatalf("best %s", pe.BestAddr)
+	}
+
+	// the second one works
+	b.addrResult(p.id, "198.51.100.5:1234", true)
+
+	pe := p.GetExport()
+	if pe.BestAddr != "198.51.100.5:1234" || pe.Addrs[1].NumOK != 1 {
+		t.Fatalf("best %s, addrs %+v", pe.BestAddr, pe.Addrs)
+	}
+	if na := p.preferredAddr(); na == nil || na.Addr != "198.51.100.5:1234" {
+		t.Fatalf("preferred %v", na)
+	}
+
+	// a single failure does not change our mind
+	b.addrResult(p.id, "198.51.100.5:1234", false)
+	if pe := p.GetExport(); pe.BestAddr != "198.51.100.5:1234" {
+		t.Fatalf("best %s", pe.BestAddr)
+	}
+
+	// repeated failures do
+	for i := 1; i < ADDRFAIL; i++ {
+		b.addrResult(p.id, "198.51.100.5:1234", false)
+	}
+
+	pe = p.GetExport()
+	if pe.BestAddr != "203.0.113.5:1234" || pe.Addrs[1].ConsecFail != ADDRFAIL || p.preferredAddr() != nil {
+		t.Fatalf("best %s, addrs %+v", pe.BestAddr, pe.Addrs)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+	b.Update(&tPeer{tRecord(b, "mrtesty@flaky", STATUS_UP)})
+
+	selectable := func() bool {
+		for _, pe := range b.selectView().Kibitzers {
+			if pe.Id == "mrtesty@flaky" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// maybe down, keep checking
+	for i := 0; i < MAXFAIL; i++ {
+		b.PeerDn("mrtesty@flaky")
+	}
+	if pe := b.Get("mrtesty@flaky").GetExport(); pe.Status != STATUS_MAYBEDN || !pe.NextTry.IsZero() || !selectable() {
+		t.Fatalf("backing off %s %v", pe.Status, pe.NextTry)
+	}
+
+	prev := time.Duration(0)
+	for i := 1; i <= 3; i++ {
+		b.PeerDn("mrtesty@flaky")
+		pe := b.Get("mrtesty@flaky").GetExport()
+		d := pe.NextTry.Sub(pe.LastTry)
+
+		if d < BACKOFF<<uint(i-1)/2 || d > BACKOFF<<uint(i-1) || d <= prev/2 {
+			t.Fatalf("failure %d: backoff %v", i, d)
+		}
+		prev = d
+	}
+
+	// not a candidate while backing off
+	if selectable() {
+		t.Fatalf("backed off peer selected")
+	}
+
+	b.PeerUp("mrtesty@flaky")
+	if pe := b.Get("mrtesty@flaky").GetExport(); !pe.NextTry.IsZero() {
+		t.Fatalf("backoff not reset")
+	}
+
+	if d := backoff(100); d > MAXBACKOFF {
+		t.Fatalf("backoff %v", d)
+	}
+}
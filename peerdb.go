@@ -11,11 +11,13 @@ import (
 	"time"
 
 	"github.com/jaw0/kibitz/lamport"
+	"github.com/jaw0/kibitz/myinfo"
 )
 
 const (
 	KEEPDOWN = 10 * lamport.Minute // keep data about down servers for how long?
 	KEEPLOST = 10 * lamport.Minute // keep data about servers we have not heard about for how long?
+	RESCAN   = time.Minute         // look for network changes how often?
 )
 
 var serverupds = expvar.NewInt("kibitz_server_updates")
@@ -38,9 +40,39 @@ type Conf struct {
 	Rack        string
 	Promiscuous bool
 	Port        int
+	MaxHops     int  // drop records that have been relayed more than this
+	UniqueId    bool // refuse to start if our id is already in use
+	Metadata    map[string]string
+	Rescan      time.Duration // look for network changes how often? (-1 to disable)
+	Locator     myinfo.Locator
+	Selector    Selector      // how to pick peers to kibitz with. default RandomSelector
+	SpreadTime  time.Duration // target time for news to reach everyone, in a large cluster
+	MinInterval time.Duration // never kibitz more often than this
+	MaxInterval time.Duration // or less often than this
+	HostRules   []string      // regexps with named groups (dc, rack) for parsing the hostname
+	// addresses peers should use to reach us (NAT, containers, ...)
+	Advertise     []*NetInfo
+	AdvertiseOnly bool // advertise only these, not the discovered addresses
+	// which interfaces + addresses to use (globs, cidrs)
+	Interfaces        []string
+	ExcludeInterfaces []string
+	IncludeNets       []string
+	ExcludeNets       []string
+	PrivateNets       []string // treated as nat domains. default: RFC 1918 + RFC 4193
+	// cidr => nat domain name. private networks that can reach each other
+	// across datacenters should map to the same name
+	NatMap map[string]string
+	// remember removed peers for how long?
+	TombstoneTime time.Duration
+	// protect against broken or hostile peers
+	Limits Limits
+	// advertise ourself as degraded or draining. see also RegisterHealthCheck
+	HealthCheck    func() Health
+	HealthInterval time.Duration
 }
 
 type DB struct {
+	fprint      uint64 // atomic. first for alignment
 	iface       infoer
 	sys         string
 	id          string
@@ -50,20 +82,48 @@ type DB struct {
 	host        string
 	promiscuous bool // collect data on all system types?
 	port        int  // tcp port
+	rescan      time.Duration
+	advertise   []*NetInfo
+	advOnly     bool
+	netconf     myinfo.NetConf
+	selector    Selector
+	spreadTime  time.Duration
+	churn       churnMeter
+	minInterval time.Duration
+	maxInterval time.Duration
+	tombTime    time.Duration
+	natmap      myinfo.NatMap
+	maxhops     int
+	uniqueId    bool
+	dup         dupCheck
 	seed        []string
-	myaddrs     map[string]string
-	mydoms      map[string]bool
 	nmon        *netMon
-	netinfo     []*NetInfo
-	bestaddr    string
+	bcast       *bcaster
+	rumors      *rumorMill
+	bans        *banList
+	limits      *limiter
+	health      *healthChecks
+	healthFreq  time.Duration
 	stop        chan struct{}
+	kick        chan struct{}
 	done        sync.WaitGroup
 	clock       *lamport.Clock
 	bootTime    uint64
+	updlock     sync.Mutex   // serialize UpdateSelf
+	selflock    sync.RWMutex // protects info about myself:
+	myaddrs     map[string]string
+	mydoms      map[string]bool
+	netinfo     []*NetInfo
+	discovered  []*NetInfo
+	bestaddr    string
+	metadata    map[string]string
+	timeConf    uint64
+	myconfs     map[uint64]bool
 	lock        sync.RWMutex
 	allpeers    map[string]*Peer
 	skeptical   map[string]*Peer
 	kibitzers   map[string]*Peer
+	tombs       map[string]*Tombstone
 }
 
 func New(c *Conf) *DB {
@@ -77,31 +137,111 @@ func New(c *Conf) *DB {
 		rack:        c.Rack,
 		promiscuous: c.Promiscuous,
 		port:        c.Port,
+		rescan:      c.Rescan,
+		advertise:   c.Advertise,
+		advOnly:     c.AdvertiseOnly,
+		selector:    c.Selector,
+		spreadTime:  c.SpreadTime,
+		minInterval: c.MinInterval,
+		maxInterval: c.MaxInterval,
+		tombTime:    c.TombstoneTime,
+		maxhops:     c.MaxHops,
+		uniqueId:    c.UniqueId,
 		seed:        c.Seed,
 		clock:       lamport.New(),
 		nmon:        netMonNew(),
+		bcast:       bcasterNew(),
+		rumors:      rumorMillNew(),
+		bans:        banListNew(),
+		limits:      limiterNew(c.Limits),
+		health:      healthChecksNew(c.HealthCheck),
+		healthFreq:  c.HealthInterval,
+		metadata:    copyMetadata(c.Metadata),
 		stop:        make(chan struct{}),
+		kick:        make(chan struct{}, 1),
 		myaddrs:     make(map[string]string),
 		mydoms:      make(map[string]bool),
+		myconfs:     make(map[uint64]bool),
 		allpeers:    make(map[string]*Peer),
 		skeptical:   make(map[string]*Peer),
 		kibitzers:   make(map[string]*Peer),
+		tombs:       make(map[string]*Tombstone),
+	}
+
+	pdb.netconf = myinfo.NetConf{
+		Interfaces:        c.Interfaces,
+		ExcludeInterfaces: c.ExcludeInterfaces,
+		Include:           c.IncludeNets,
+		Exclude:           c.ExcludeNets,
+		Private:           c.PrivateNets,
+		NatMap:            c.NatMap,
+	}
+
+	if err := pdb.netconf.Check(); err != nil {
+		dl.Problem("invalid network config, using defaults: %v", err)
+		pdb.netconf = myinfo.NetConf{}
+	}
+
+	natmap, err := myinfo.ParseNatMap(c.NatMap)
+	if err != nil {
+		dl.Problem("invalid nat map: %v", err)
 	}
+	pdb.natmap = natmap
 
 	pdb.bootTime = pdb.clock.Now().Uint64()
+	pdb.timeConf = pdb.bootTime
+	pdb.myconfs[pdb.timeConf] = true
 
 	if pdb.env == "" {
 		pdb.env = "dev"
 	}
+	if pdb.maxhops <= 0 {
+		pdb.maxhops = MAXHOPS
+	}
+	pdb.limits.MaxVia = limitDefault(pdb.limits.MaxVia, pdb.maxhops)
+	if pdb.rescan == 0 {
+		pdb.rescan = RESCAN
+	}
+	if pdb.selector == nil {
+		pdb.selector = RandomSelector{}
+	}
+	if pdb.spreadTime <= 0 {
+		pdb.spreadTime = SPREADTIME
+	}
+	if pdb.minInterval <= 0 {
+		pdb.minInterval = MININTERVAL
+	}
+	if pdb.maxInterval <= 0 {
+		pdb.maxInterval = MAXINTERVAL
+	}
+	if pdb.maxInterval < pdb.minInterval {
+		pdb.maxInterval = pdb.minInterval
+	}
+	if pdb.tombTime <= 0 {
+		pdb.tombTime = TOMBSTONE
+	}
+	if pdb.healthFreq <= 0 {
+		pdb.healthFreq = HEALTHCHECK
+	}
 
 	pdb.learn(c)
+	pdb.updateFingerprint()
 
 	return pdb
 }
 
-func (pdb *DB) Start() {
-	pdb.done.Add(1)
+func (pdb *DB) Start() error {
+
+	if pdb.uniqueId {
+		if err := pdb.checkUnique(); err != nil {
+			return err
+		}
+	}
+
+	pdb.done.Add(2)
 	go pdb.periodic()
+	go pdb.healthCheckLoop()
+	return nil
 }
 
 func (pdb *DB) Stop() {
@@ -113,15 +253,38 @@ func (pdb *DB) Stop() {
 
 // 3rd party reports
 func (pdb *DB) Update(px PeerImport) {
+	pdb.update(px, "")
+}
+
+// src is who told us. "" if unknown
+func (pdb *DB) update(px PeerImport, src string) {
+
+	if err := pdb.Validate(px); err != nil {
+		pdb.invalid(err, src)
+		return
+	}
 
 	pi := px.GetPeerInfo()
 
 	dl.Debug("update peer %s", pi.GetServerId())
 
+	if r := pdb.limits.sizeOK(pi); r != "" {
+		pdb.limits.reject(src, r)
+		return
+	}
+	if pi.GetServerId() == pdb.id {
+		pdb.checkSelfConflict(pi)
+	}
 	if !pdb.isOK(pi) {
 		return
 	}
 
+	pdb.recvBroadcast(pi)
+
+	if !pdb.viaOK(pi) {
+		return
+	}
+
 	serverupds.Add(1)
 
 	pdb.lock.Lock()
@@ -131,15 +294,28 @@ func (pdb *DB) Update(px PeerImport) {
 	pdb.clock.Update(lamport.ToTime(pi.GetTimeCreated()))
 	pdb.clock.Update(lamport.ToTime(pi.GetTimeChecked()))
 
+	if pdb.buried(pi) {
+		return
+	}
+
 	p := pdb.find(pi.GetServerId())
 
 	switch {
 
 	case p == nil:
+		if !pdb.limits.allowNew(src) {
+			pdb.limits.reject(src, "newpeers")
+			return
+		}
 		dl.Verbose("discovered new peer %s", pi.GetServerId())
 		p = peerNew(pdb, px, STATUS_UNKNOWN)
 		pdb.addPeer(p)
 
+	case pi.GetEvictedBy() != "":
+		// an operator's word beats anything newer the peer has said
+		pdb.evict(p, pi)
+		return
+
 	case p.status == STATUS_SCEPTICAL:
 		dl.Verbose("discovered new peer %s", pi.GetServerId())
 		pdb.upgrade(p)
@@ -152,6 +328,14 @@ func (pdb *DB) Update(px PeerImport) {
 	p.Update(px, pdb)
 
 	switch p.status {
+	case STATUS_DEAD:
+		if pi.GetEvictedBy() != "" {
+			dl.Verbose("peer %s evicted by %s", p.id, pi.GetEvictedBy())
+			pdb.kill(p, TOMB_EVICTED)
+		} else {
+			dl.Verbose("peer %s left", p.id)
+			pdb.kill(p, TOMB_LEFT)
+		}
 	case STATUS_UP, STATUS_DOWN:
 		go pdb.iface.Update(pi.GetServerId(), p.status != STATUS_DOWN, p.info.GetSubsystem() == p.pdb.sys)
 	}
@@ -160,20 +344,42 @@ func (pdb *DB) Update(px PeerImport) {
 // their reports
 func (pdb *DB) UpdateSceptical(px PeerImport) {
 
+	if err := pdb.Validate(px); err != nil {
+		pdb.invalid(err, "")
+		return
+	}
+
 	pi := px.GetPeerInfo()
 
+	if r := pdb.limits.sizeOK(pi); r != "" {
+		pdb.limits.reject(pi.GetServerId(), r)
+		return
+	}
+	if pi.GetServerId() == pdb.id {
+		pdb.checkSelfConflict(pi)
+	}
 	if !pdb.isOK(pi) {
 		return
 	}
 
+	pdb.recvBroadcast(pi)
 	serverupds.Add(1)
 
 	pdb.lock.Lock()
 	defer pdb.lock.Unlock()
 
+	if pdb.buried(pi) {
+		return
+	}
+
 	p := pdb.find(pi.GetServerId())
 
 	if p == nil {
+		// each says only who it is. limit them all together
+		if !pdb.limits.allowNew(SCEPTSRC) {
+			pdb.limits.reject(SCEPTSRC, "newpeers")
+			return
+		}
 		dl.Debug("add new scept %s", pi.GetServerId())
 		p = peerNew(pdb, px, STATUS_SCEPTICAL)
 		pdb.skeptical[p.id] = p
@@ -225,7 +431,7 @@ func (pdb *DB) PeerDn(id string) {
 	os := p.status
 
 	if os == STATUS_SCEPTICAL {
-		pdb.kill(p)
+		pdb.kill(p, TOMB_EXPIRED)
 		return
 	}
 
@@ -233,6 +439,8 @@ func (pdb *DB) PeerDn(id string) {
 
 	if os != STATUS_DOWN && p.status == STATUS_DOWN {
 		dl.Debug("peer %s is now down", id)
+		// we noticed first. tell people
+		pdb.rumor(id)
 	}
 }
 
@@ -254,6 +462,10 @@ func (pdb *DB) isOK(pi *PeerInfo) bool {
 		dl.Debug("not ok - env - %v", pi)
 		return false
 	}
+	if pdb.bans.isBanned(pi) {
+		dl.Debug("not ok - banned - %v", pi)
+		return false
+	}
 
 	if pi.GetTimeCreated() < now-KEEPLOST {
 		dl.Debug("not ok - Tchk - %v", pi)
@@ -267,6 +479,24 @@ func (pdb *DB) isOK(pi *PeerInfo) bool {
 	return true
 }
 
+func (pdb *DB) viaOK(pi *PeerInfo) bool {
+
+	if int(pi.GetHops()) > pdb.maxhops {
+		dl.Debug("not ok - hops - %v", pi)
+		return false
+	}
+
+	for _, id := range pi.GetViaPath() {
+		if id == pdb.id {
+			// it has already been through here
+			dl.Debug("not ok - loop - %v", pi)
+			return false
+		}
+	}
+
+	return true
+}
+
 // ################################################################
 
 func (pdb *DB) find(id string) *Peer {
@@ -287,6 +517,10 @@ func (pdb *DB) addPeer(p *Peer) {
 	if pdb.sys == p.info.GetSubsystem() {
 		pdb.kibitzers[p.id] = p
 	}
+
+	p.lock.Lock()
+	p.refingerprint()
+	p.lock.Unlock()
 }
 
 func (pdb *DB) upgrade(p *Peer) {
@@ -295,8 +529,9 @@ func (pdb *DB) upgrade(p *Peer) {
 	pdb.addPeer(p)
 }
 
-func (pdb *DB) kill(p *Peer) {
+func (pdb *DB) kill(p *Peer, reason TombReason) {
 
+	pdb.bury(p, reason)
 	delete(pdb.allpeers, p.id)
 	delete(pdb.skeptical, p.id)
 	delete(pdb.kibitzers, p.id)
@@ -307,12 +542,18 @@ func (pdb *DB) kill(p *Peer) {
 // ################################################################
 
 func (p *DB) Rack() string {
+	p.selflock.RLock()
+	defer p.selflock.RUnlock()
 	return p.rack
 }
 func (p *DB) Datacenter() string {
+	p.selflock.RLock()
+	defer p.selflock.RUnlock()
 	return p.dc
 }
 func (p *DB) Host() string {
+	p.selflock.RLock()
+	defer p.selflock.RUnlock()
 	return p.host
 }
 func (p *DB) Env() string {
@@ -321,7 +562,10 @@ func (p *DB) Env() string {
 func (p *DB) Id() string {
 	return p.id
 }
+
 func (p *DB) DomOK(dom string) bool {
+	p.selflock.RLock()
+	defer p.selflock.RUnlock()
 	return p.mydoms[dom]
 }
 func (p *DB) ClockBoot() uint64 {
@@ -331,6 +575,20 @@ func (p *DB) ClockNow() uint64 {
 	return p.clock.Inc().Uint64()
 }
 
+// Networks returns the state of the networks we are connected to
+func (p *DB) Networks() []NetStatus {
+	return p.nmon.Status()
+}
+
+// the nat domain of a peer's address, according to our config if we have one
+func (pdb *DB) natdomOf(ni *NetInfo) string {
+
+	if dom, ok := pdb.natmap.Lookup(ni.GetAddr()); ok {
+		return dom
+	}
+	return ni.GetNatdom()
+}
+
 func (pdb *DB) Get(id string) *Peer {
 	pdb.lock.Lock()
 	defer pdb.lock.Unlock()
@@ -357,16 +615,24 @@ func (pdb *DB) GetAll() []*Peer {
 	return all
 }
 
+// NB - evicted peers are included, wrapped by the transport, with isup false
 func (pdb *DB) ForAllData(fnc func(string, bool, interface{})) {
 	pdb.lock.RLock()
 	defer pdb.lock.RUnlock()
 
 	for _, p := range pdb.allpeers {
-		fnc(p.id, p.status == STATUS_UP, p.GetData())
+		fnc(p.id, p.isAvailable(), p.GetData())
+	}
+
+	// evictions
+	for _, t := range pdb.tombs {
+		if d := pdb.evictionData(t); d != nil {
+			fnc(t.Id, false, d)
+		}
 	}
 
 	// and myself
-	fnc(pdb.id, true, pdb.Myself())
+	fnc(pdb.id, isAvailable(STATUS_UP, pdb.Health()), pdb.myselfToSend())
 }
 
 func (pdb *DB) ForAllExport(fnc func(*Export)) {
@@ -382,24 +648,43 @@ func (pdb *DB) ForAllExport(fnc func(*Export)) {
 
 func (pdb *DB) periodic() {
 
+	lastScan := time.Now()
+	var lastPush time.Time
+
 	for {
+		pdb.pushRumors()
+		lastPush = time.Now()
 		pdb.kibitzWithRandomPeer()
 		pdb.Cleanup()
+		pdb.checkNetworks()
 
-		delay := 5 * time.Second
-
-		if len(pdb.kibitzers) == 0 || len(pdb.skeptical) != 0 {
-			// faster at startup
-			delay = time.Second
+		if pdb.rescan > 0 && time.Since(lastScan) > pdb.rescan {
+			pdb.Rescan()
+			lastScan = time.Now()
 		}
 
-		select {
-		case <-pdb.stop:
-			dl.Debug("done")
-			pdb.done.Done()
-			return
-		case <-time.After(delay):
-			continue
+		next := time.After(pdb.interval())
+		var push <-chan time.Time
+
+	wait:
+		for {
+			select {
+			case <-pdb.stop:
+				dl.Debug("done")
+				pdb.done.Done()
+				return
+			case <-pdb.kick:
+				// push news now, but not more often than minInterval
+				if push == nil {
+					push = time.After(pdb.minInterval - time.Since(lastPush))
+				}
+			case <-push:
+				push = nil
+				pdb.pushRumors()
+				lastPush = time.Now()
+			case <-next:
+				break wait
+			}
 		}
 	}
 }
@@ -409,11 +694,17 @@ func (pdb *DB) Cleanup() {
 	pdb.lock.Lock()
 	defer pdb.lock.Unlock()
 
+	pdb.bcast.cleanup(pdb.clock.Now().Uint64(), len(pdb.kibitzers))
+
 	// remove old entries
 	for id, p := range pdb.allpeers {
 		if !pdb.isOK(p.info) {
 			dl.Debug("deleting %s", id)
-			pdb.kill(p)
+			pdb.kill(p, TOMB_EXPIRED)
 		}
 	}
+	pdb.cleanupTombs()
+	pdb.bans.cleanup()
+
+	pdb.updateFingerprint()
 }
@@ -13,6 +13,10 @@ type randPeer struct {
 	count int
 	p     *Peer
 }
+type randExport struct {
+	count int
+	p     *Export
+}
 type randNet struct {
 	count int
 	p     *NetInfo
@@ -48,6 +52,17 @@ func (rp *randNet) peer() *NetInfo {
 	return rp.p
 }
 
+// ################################################################
+
+func (rp *randExport) maybe(p *Export) {
+
+	rp.count++
+
+	if random_n(rp.count) == 0 {
+		rp.p = p
+	}
+}
+
 //################################################################
 
 func random_n(n int) int {
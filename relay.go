@@ -0,0 +1,171 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 18:40 (EDT)
+// Function: reach peers on remote private networks through a peer on that network
+
+package kibitz
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"time"
+)
+
+// less than the requestor's timeout, so it hears that the target failed
+const RELAYTIMEOUT = TIMEOUT / 2
+
+var relayreqs = expvar.NewInt("kibitz_relay_reqs")
+var relayerrs = expvar.NewInt("kibitz_relay_fail")
+
+// transports that implement this can relay requests through a peer
+// SendRelay asks the peer at relay to exchange myself with the peer at addr
+type relayer interface {
+	SendRelay(relay string, addr string, timeout time.Duration, myself PeerImport) ([]PeerImport, error)
+}
+
+// the relay was reached, but it could not reach the target.
+// transports should return this from SendRelay so the failure is blamed on the target.
+// any other error is blamed on the relay.
+type RelayError struct {
+	Target string
+	Err    error
+}
+
+func (e *RelayError) Error() string {
+	return fmt.Sprintf("relay to %s failed: %v", e.Target, e.Err)
+}
+
+func (e *RelayError) Unwrap() error {
+	return e.Err
+}
+
+func (pdb *DB) canRelay() bool {
+	_, ok := pdb.iface.(relayer)
+	return ok
+}
+
+// is the domain a private network that we are not on?
+func (pdb *DB) isRemote(natdom string) bool {
+
+	if natdom == "[seed]" {
+		return false
+	}
+	_, known := pdb.nmon.IsUp(natdom)
+	return !known
+}
+
+func (pdb *DB) relayWith(peerAddr string, natdom string, peerId string) {
+
+	rx, ok := pdb.iface.(relayer)
+	if !ok {
+		return
+	}
+
+	relay := pdb.findRelay(natdom, peerId)
+	if relay == nil {
+		dl.Debug("kibitz with peer - skipping - no relay to %s [%s]", peerAddr, natdom)
+		return
+	}
+
+	relayAddr, relayDom, relayId := pdb.useAddr(relay)
+	if relayAddr == "" || pdb.isRemote(relayDom) {
+		return
+	}
+
+	dl.Debug("kibitz with peer %s (%s) via %s (%s)", peerAddr, peerId, relayAddr, relayId)
+
+	peerList, err := rx.SendRelay(relayAddr, peerAddr, TIMEOUT, pdb.myselfToSend())
+	relayreqs.Add(1)
+
+	if err != nil {
+		relayerrs.Add(1)
+
+		var rerr *RelayError
+		if !errors.As(err, &rerr) {
+			// the relay is down. we know nothing about the target
+			dl.Debug(" => relay down err %v", err)
+			pdb.PeerDn(relayId)
+			pdb.addrResult(relayId, relayAddr, false)
+			return
+		}
+
+		dl.Debug(" => down err %v", err)
+		pdb.PeerDn(peerId)
+	} else {
+		pdb.updateFrom(peerId, peerList)
+		pdb.PeerUp(peerId)
+	}
+
+	// the relay is up
+	pdb.PeerUp(relayId)
+	pdb.addrResult(relayId, relayAddr, true)
+	pdb.nmon.SetUp(relayDom)
+}
+
+// find an up peer that is on the private network
+func (pdb *DB) findRelay(natdom string, notId string) *Peer {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	relay := &randPeer{}
+
+	for id, p := range pdb.kibitzers {
+		if id == notId {
+			continue
+		}
+
+		p.lock.Lock()
+		if p.status == STATUS_UP {
+			for _, ni := range p.info.GetNetInfo() {
+				if pdb.natdomOf(ni) == natdom {
+					relay.maybe(p)
+					break
+				}
+			}
+		}
+		p.lock.Unlock()
+	}
+
+	return relay.peer()
+}
+
+// Relay is the server side of a relayed request. the transport should call it
+// and return the results to the requestor. failures reaching the target are
+// returned as a *RelayError
+func (pdb *DB) Relay(addr string, px PeerImport) ([]PeerImport, error) {
+
+	if pdb.IsBanned(px) {
+		return nil, fmt.Errorf("relay request from banned peer")
+	}
+
+	// only to our peers. we are not an open proxy
+	if !pdb.isPeerAddr(addr) {
+		return nil, fmt.Errorf("relay to unknown address %s", addr)
+	}
+
+	res, err := pdb.iface.Send(addr, RELAYTIMEOUT, px)
+	if err != nil {
+		return nil, &RelayError{Target: addr, Err: err}
+	}
+
+	return res, nil
+}
+
+func (pdb *DB) isPeerAddr(addr string) bool {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	for _, p := range pdb.kibitzers {
+		p.lock.Lock()
+		ok := hasAddr(p.info, addr)
+		p.lock.Unlock()
+
+		if ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 19:05 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// an in memory network
+type tRelayIface struct {
+	tIface
+	net     map[string]*DB
+	timeout time.Duration // of the last send
+}
+
+func (t *tRelayIface) Send(addr string, timeout time.Duration, px PeerImport) ([]PeerImport, error) {
+
+	t.timeout = timeout
+	srv := t.net[addr]
+	if srv == nil {
+		return nil, errors.New("connection refused")
+	}
+
+	srv.Update(px)
+	return []PeerImport{srv.Myself()}, nil
+}
+
+func (t *tRelayIface) SendRelay(relay string, addr string, timeout time.Duration, px PeerImport) ([]PeerImport, error) {
+
+	srv := t.net[relay]
+	if srv == nil {
+		return nil, errors.New("connection refused")
+	}
+
+	res, err := srv.Relay(addr, px)
+
+	if err != nil && srv.iface.(*tRelayIface).timeout >= timeout {
+		// we gave up waiting before the relay did
+		return nil, errors.New("timeout")
+	}
+
+	return res, err
+}
+
+func TestRelay(t *testing.T) {
+
+	net := make(map[string]*DB)
+
+	db := func(host string, adv ...*NetInfo) *DB {
+		pdb := New(&Conf{
+			System:        "mrtesty",
+			Environment:   "test",
+			Hostname:      host,
+			Port:          1234,
+			Iface:         &tRelayIface{net: net},
+			Advertise:     adv,
+			AdvertiseOnly: true,
+		})
+		for _, ni := range adv {
+			net[ni.Addr] = pdb
+		}
+		return pdb
+	}
+
+	a := db("a.phl1.example.com", &NetInfo{Addr: "203.0.113.1:1234"})
+	b := db("b.nyc1.example.com", &NetInfo{Addr: "203.0.113.2:1234"}, &NetInfo{Addr: "10.1.0.2:1234", Natdom: "nyc1"})
+	c := db("c.nyc1.example.com", &NetInfo{Addr: "10.1.0.3:1234", Natdom: "nyc1"})
+
+	b.Update(c.Myself())
+	a.Update(b.Myself())
+	a.Update(c.Myself())
+
+	if addr, dom, _ := a.useAddr(a.Get(c.Id())); addr != "10.1.0.3:1234" || !a.isRemote(dom) {
+		t.Fatalf("expected remote addr, got %s [%s]", addr, dom)
+	}
+
+	// ok
+	n := relayreqs.Value()
+	a.relayWith("10.1.0.3:1234", "nyc1", c.Id())
+
+	if relayreqs.Value() != n+1 {
+		t.Fatalf("relay not used")
+	}
+	if pe := a.Get(c.Id()).GetExport(); pe.Status != STATUS_UP || pe.LastTry.IsZero() {
+		t.Fatalf("target status %s", pe.Status)
+	}
+	if c.Get(a.Id()) == nil {
+		t.Fatalf("target did not learn about us")
+	}
+
+	// target is down
+	delete(net, "10.1.0.3:1234")
+	a.relayWith("10.1.0.3:1234", "nyc1", c.Id())
+
+	if pe := a.Get(c.Id()).GetExport(); pe.Status != STATUS_MAYBEDN {
+		t.Fatalf("target status %s", pe.Status)
+	}
+	if pe := a.Get(b.Id()).GetExport(); pe.Status != STATUS_UP {
+		t.Fatalf("relay status %s", pe.Status)
+	}
+
+	// relay is down
+	net["10.1.0.3:1234"] = c
+	delete(net, "203.0.113.2:1234")
+	a.relayWith("10.1.0.3:1234", "nyc1", c.Id())
+
+	if pe := a.Get(b.Id()).GetExport(); pe.Status != STATUS_MAYBEDN {
+		t.Fatalf("relay status %s", pe.Status)
+	}
+	if pe := a.Get(c.Id()).GetExport(); pe.Status != STATUS_MAYBEDN {
+		t.Fatalf("target status changed %s", pe.Status)
+	}
+
+	// not an open relay
+	if _, err := b.Relay("198.51.100.1:1234", a.Myself()); err == nil {
+		t.Fatalf("relayed to unknown addr")
+	}
+}
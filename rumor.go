@@ -0,0 +1,187 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 20:50 (EDT)
+// Function: push news immediately, instead of waiting to be asked
+
+package kibitz
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	RUMORFANOUT = 3  // push to this many peers
+	RUMORXMIT   = 3  // retransmit each change this many times
+	MAXRUMOR    = 16 // max records per push
+)
+
+var rumorsent = expvar.NewInt("kibitz_rumors_sent")
+
+type rumorMill struct {
+	lock    sync.Mutex
+	pending map[string]*rumor
+}
+
+type rumor struct {
+	when time.Time
+	sent int
+}
+
+func rumorMillNew() *rumorMill {
+	return &rumorMill{
+		pending: make(map[string]*rumor),
+	}
+}
+
+// Kick pushes any recent changes to a few peers now.
+// (or as soon as MinInterval allows)
+func (pdb *DB) Kick() {
+	pdb.kickNow()
+}
+
+// something we noticed about this peer (or ourself) changed. tell people.
+func (pdb *DB) rumor(id string) {
+
+	pdb.rumors.add(id)
+	pdb.kickNow()
+}
+
+func (rm *rumorMill) add(id string) {
+
+	rm.lock.Lock()
+	defer rm.lock.Unlock()
+
+	rm.pending[id] = &rumor{when: time.Now()}
+}
+
+// the most recent changes
+func (rm *rumorMill) take() []string {
+
+	rm.lock.Lock()
+	defer rm.lock.Unlock()
+
+	var ids []string
+	for id := range rm.pending {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return rm.pending[ids[i]].when.After(rm.pending[ids[j]].when) })
+
+	if len(ids) > MAXRUMOR {
+		ids = ids[:MAXRUMOR]
+	}
+
+	for _, id := range ids {
+		r := rm.pending[id]
+		r.sent++
+		if r.sent >= RUMORXMIT {
+			delete(rm.pending, id)
+		}
+	}
+
+	return ids
+}
+
+// ################################################################
+
+func (pdb *DB) pushRumors() {
+
+	ids := pdb.rumors.take()
+	if len(ids) == 0 {
+		return
+	}
+
+	dl.Debug("pushing %d changes", len(ids))
+
+	var wg sync.WaitGroup
+
+	for _, p := range pdb.rumorTargets() {
+		addr, natdom, id := pdb.useAddr(p)
+		if addr == "" || pdb.isRemote(natdom) {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pdb.pushTo(addr, natdom, id, ids)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (pdb *DB) pushTo(addr string, natdom string, id string, ids []string) {
+
+	dx, ok := pdb.iface.(deltaer)
+	if !ok {
+		// the peer learns about us, we learn about everything
+		pdb.kibitzWith(addr, natdom, id)
+		return
+	}
+
+	recs := pdb.rumorRecords(ids, id)
+	err := dx.SendDelta(addr, TIMEOUT, pdb.myselfToSend(), recs)
+
+	if err != nil {
+		dl.Debug(" => down err %v", err)
+		pdb.PeerDn(id)
+		pdb.addrResult(id, addr, false)
+		return
+	}
+
+	rumorsent.Add(int64(len(recs)))
+	pdb.PeerUp(id)
+	pdb.addrResult(id, addr, true)
+	pdb.nmon.SetUp(natdom)
+}
+
+// a few random up peers
+func (pdb *DB) rumorTargets() []*Peer {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	var up []*Peer
+	for _, p := range pdb.kibitzers {
+		p.lock.Lock()
+		if p.status == STATUS_UP {
+			up = append(up, p)
+		}
+		p.lock.Unlock()
+	}
+
+	for i := 0; i < len(up) && i < RUMORFANOUT; i++ {
+		j := i + random_n(len(up)-i)
+		up[i], up[j] = up[j], up[i]
+	}
+	if len(up) > RUMORFANOUT {
+		up = up[:RUMORFANOUT]
+	}
+
+	return up
+}
+
+// the records to push. (our own is sent as myself)
+func (pdb *DB) rumorRecords(ids []string, notId string) []PeerImport {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	var recs []PeerImport
+	for _, id := range ids {
+		if id == notId || id == pdb.id {
+			continue
+		}
+		if p := pdb.allpeers[id]; p != nil {
+			recs = append(recs, p.GetData().(PeerImport))
+		} else if t := pdb.tombs[id]; t != nil && t.info != nil {
+			recs = append(recs, pdb.evictionData(t))
+		}
+	}
+
+	return recs
+}
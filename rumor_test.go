@@ -0,0 +1,161 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 21:15 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type tPushIface struct {
+	tIface
+	net map[string]*DB
+}
+
+func (t *tPushIface) SendDigest(string, time.Duration, PeerImport, *Digest) ([]PeerImport, *Digest, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+func (t *tPushIface) SendDelta(addr string, timeout time.Duration, px PeerImport, recs []PeerImport) error {
+
+	srv := t.net[addr]
+	if srv == nil {
+		return errors.New("connection refused")
+	}
+
+	srv.RecvDelta(px, recs)
+	return nil
+}
+
+func TestRumorMill(t *testing.T) {
+
+	rm := rumorMillNew()
+	rm.add("old")
+	time.Sleep(time.Millisecond)
+	rm.add("new")
+
+	for i := 0; i < RUMORXMIT; i++ {
+		ids := rm.take()
+		if len(ids) != 2 || ids[0] != "new" {
+			t.Fatalf("round %d: %v", i, ids)
+		}
+	}
+
+	if ids := rm.take(); len(ids) != 0 {
+		t.Fatalf("retransmitted too many times: %v", ids)
+	}
+}
+
+func TestPushRumors(t *testing.T) {
+
+	net := make(map[string]*DB)
+
+	db := func(host string, addr string) *DB {
+		pdb := New(&Conf{
+			System:        "mrtesty",
+			Environment:   "test",
+			Hostname:      host,
+			Port:          1234,
+			Iface:         &tPushIface{net: net},
+			Advertise:     []*NetInfo{{Addr: addr}},
+			AdvertiseOnly: true,
+		})
+		net[addr] = pdb
+		return pdb
+	}
+
+	a := db("a.phl1.example.com", "203.0.113.1:1234")
+	b := db("b.phl1.example.com", "203.0.113.2:1234")
+	c := db("c.phl1.example.com", "203.0.113.3:1234")
+
+	a.Update(b.Myself())
+	a.Update(c.Myself())
+
+	// hearsay is not news
+	n := rumorsent.Value()
+	a.pushRumors()
+
+	if b.Get(c.Id()) != nil || rumorsent.Value() != n {
+		t.Fatalf("pushed third party news")
+	}
+
+	// a finds that c is down. that is news.
+	for i := 0; i <= MAXFAIL; i++ {
+		a.PeerDn(c.Id())
+	}
+	a.pushRumors()
+
+	if p := b.Get(c.Id()); p == nil || p.GetExport().Status != STATUS_DOWN {
+		t.Fatalf("news did not spread")
+	}
+	if rumorsent.Value() != n+1 {
+		t.Fatalf("sent %d", rumorsent.Value()-n)
+	}
+}
+
+type tKickIface struct {
+	tPushIface
+	digests int32
+	deltas  int32
+}
+
+func (t *tKickIface) SendDigest(string, time.Duration, PeerImport, *Digest) ([]PeerImport, *Digest, error) {
+	atomic.AddInt32(&t.digests, 1)
+	return nil, &Digest{}, nil
+}
+
+func (t *tKickIface) SendDelta(string, time.Duration, PeerImport, []PeerImport) error {
+	atomic.AddInt32(&t.deltas, 1)
+	return nil
+}
+
+func TestKick(t *testing.T) {
+
+	ti := &tKickIface{}
+	a := New(&Conf{
+		System:      "mrtesty",
+		Environment: "test",
+		Hostname:    "a.phl1.example.com",
+		Port:        1234,
+		Iface:       ti,
+		MinInterval: 100 * time.Millisecond,
+		MaxInterval: time.Hour,
+		SpreadTime:  time.Hour,
+		Rescan:      -1,
+		Selector:    &RoundRobin{},
+	})
+	b := New(&Conf{
+		System:        "mrtesty",
+		Environment:   "test",
+		Hostname:      "b.phl1.example.com",
+		Port:          1234,
+		Iface:         tIface{},
+		Advertise:     []*NetInfo{{Addr: "203.0.113.2:1234"}},
+		AdvertiseOnly: true,
+	})
+	a.Update(b.Myself())
+
+	a.Start()
+	defer a.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	// lots of news, all at once
+	for i := 0; i < 20; i++ {
+		a.rumor(a.Id())
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// pushed, no faster than MinInterval. without a full round
+	if n := atomic.LoadInt32(&ti.digests); n != 1 {
+		t.Fatalf("%d full rounds", n)
+	}
+	if n := atomic.LoadInt32(&ti.deltas); n < 1 || n > 3 {
+		t.Fatalf("%d pushes", n)
+	}
+}
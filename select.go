@@ -0,0 +1,325 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 19:30 (EDT)
+// Function: pick a peer to kibitz with
+
+package kibitz
+
+import (
+	"expvar"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// worst case time until a peer is probed, in round robin mode
+var probebound = expvar.NewInt("kibitz_probe_bound_ms")
+
+// a read-only view of the candidates
+type SelectView struct {
+	Kibitzers []*Export
+	Skeptical []*Export
+	NumSeeds  int
+}
+
+type Selector interface {
+	// return the id of the peer to kibitz with, or "" to use a seed
+	Select(*SelectView) string
+}
+
+// the default
+type RandomSelector struct{}
+
+// choose with probability proportional to the weights
+type WeightedSelector struct {
+	SameRack  float64
+	SameDC    float64
+	Remote    float64
+	Sceptical float64
+	MaybeDn   float64 // multiplier for peers that may be down
+	Seed      float64
+}
+
+// SWIM style. shuffle, then visit each in turn.
+// each pass also visits a seed (if there are any), and sceptical peers
+// are interleaved with the pass. see rrBound.
+// new peers are inserted at a random position in the remainder of the pass
+type RoundRobin struct {
+	lock  sync.Mutex
+	order []string
+	known map[string]bool
+	pos   int
+	skept bool // the last one was sceptical
+}
+
+// ################################################################
+
+func (pdb *DB) selectPeer() *Peer {
+
+	id := pdb.selector.Select(pdb.selectView())
+	if id == "" {
+		return nil
+	}
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	return pdb.find(id)
+}
+
+func (pdb *DB) selectView() *SelectView {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	v := &SelectView{
+		NumSeeds: len(pdb.seed),
+	}
+	now := time.Now()
+
+	for _, p := range pdb.kibitzers {
+		pe := p.GetExport()
+		if pe.Status == STATUS_DOWN && pe.NextTry.After(now) {
+			// backing off
+			continue
+		}
+		v.Kibitzers = append(v.Kibitzers, pe)
+	}
+	for _, p := range pdb.skeptical {
+		v.Skeptical = append(v.Skeptical, p.GetExport())
+	}
+
+	return v
+}
+
+// ################################################################
+
+func (RandomSelector) Select(v *SelectView) string {
+
+	oldLimit := time.Now().Add(OLDTIMER)
+
+	old := &randExport{}
+	local := &randExport{}
+	away := &randExport{}
+	check := &randExport{}
+	skept := &randExport{}
+
+	nall := 0
+
+	for _, pe := range v.Kibitzers {
+		nall++
+
+		if pe.Status == STATUS_MAYBEDN {
+			check.maybe(pe)
+		}
+
+		if pe.LastTry.Before(oldLimit) {
+			old.maybe(pe)
+		}
+
+		if pe.IsSameDC {
+			local.maybe(pe)
+		} else {
+			away.maybe(pe)
+		}
+	}
+
+	for _, pe := range v.Skeptical {
+		skept.maybe(pe)
+	}
+
+	// first prefer anything sceptical
+	usePeer := skept.p
+
+	// then (maybe) anything pending
+	maybeUse(usePeer, check.p, 5)
+
+	// then (maybe) something about to expire
+	maybeUse(usePeer, old.p, 5)
+
+	// then (maybe) something far away
+	k := 5
+	if local.count < 5 {
+		// not very many locally, use more far
+		k = 2
+	}
+	if usePeer == nil && away.p != nil && random_n(k) == 0 {
+		usePeer = away.p
+	}
+
+	// otherwise prefer local
+	if usePeer == nil {
+		usePeer = local.p
+	}
+
+	// sometimes, use seed. so we can recover from a partition
+	if random_n(2*nall+2) == 0 {
+		usePeer = nil
+	}
+
+	if usePeer == nil {
+		return ""
+	}
+	return usePeer.Id
+}
+
+func maybeUse(curr *Export, nxt *Export, n int) *Export {
+
+	if nxt == nil {
+		return curr
+	}
+	if curr == nil || random_n(n) == 0 {
+		return nxt
+	}
+	return curr
+}
+
+// ################################################################
+
+func (w *WeightedSelector) Select(v *SelectView) string {
+
+	ws := *w
+	if ws == (WeightedSelector{}) {
+		ws = WeightedSelector{SameRack: 1, SameDC: 1, Remote: 0.5, Sceptical: 4, MaybeDn: 2, Seed: 0.5}
+	}
+
+	var ids []string
+	var weights []float64
+	total := 0.0
+
+	add := func(id string, wt float64) {
+		if wt <= 0 {
+			return
+		}
+		ids = append(ids, id)
+		weights = append(weights, wt)
+		total += wt
+	}
+
+	for _, pe := range v.Kibitzers {
+		wt := ws.Remote
+		switch {
+		case pe.IsSameRack && pe.IsSameDC:
+			wt = ws.SameRack
+		case pe.IsSameDC:
+			wt = ws.SameDC
+		}
+		if pe.Status == STATUS_MAYBEDN && ws.MaybeDn > 0 {
+			wt *= ws.MaybeDn
+		}
+		add(pe.Id, wt)
+	}
+	for _, pe := range v.Skeptical {
+		add(pe.Id, ws.Sceptical)
+	}
+	if v.NumSeeds > 0 {
+		add("", ws.Seed)
+	}
+
+	if total == 0 {
+		return ""
+	}
+
+	r := rand.Float64() * total
+
+	for i, wt := range weights {
+		r -= wt
+		if r < 0 {
+			return ids[i]
+		}
+	}
+	return ids[len(ids)-1]
+}
+
+// ################################################################
+
+func (rr *RoundRobin) Select(v *SelectView) string {
+
+	rr.lock.Lock()
+	defer rr.lock.Unlock()
+
+	// check sceptical peers every other round
+	if len(v.Skeptical) != 0 && !rr.skept {
+		rr.skept = true
+		return v.Skeptical[random_n(len(v.Skeptical))].Id
+	}
+	rr.skept = false
+
+	current := make(map[string]bool, len(v.Kibitzers))
+	for _, pe := range v.Kibitzers {
+		current[pe.Id] = true
+	}
+
+	if rr.known == nil {
+		rr.known = make(map[string]bool)
+	}
+	for _, pe := range v.Kibitzers {
+		if !rr.known[pe.Id] {
+			rr.insert(pe.Id)
+		}
+	}
+
+	for pass := 0; pass < 2; pass++ {
+		for rr.pos < len(rr.order) {
+			id := rr.order[rr.pos]
+			rr.pos++
+
+			if current[id] {
+				return id
+			}
+		}
+
+		rr.shuffle(current)
+
+		if v.NumSeeds > 0 {
+			// once a pass, so we can recover from a partition
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// worst case number of rounds between probes of a peer.
+// a pass is each peer + a seed, doubled if sceptical peers are interleaved.
+// a peer may be first in one pass, and last in the next.
+func rrBound(nkibitz int, nseeds int, nskept int) int {
+
+	pass := nkibitz
+	if nseeds > 0 {
+		pass++
+	}
+	if nskept > 0 {
+		pass *= 2
+	}
+	if pass == 0 {
+		return 0
+	}
+
+	return 2*pass - 1
+}
+
+// start a new pass
+func (rr *RoundRobin) shuffle(current map[string]bool) {
+
+	rr.order = rr.order[:0]
+	rr.known = make(map[string]bool, len(current))
+	rr.pos = 0
+
+	for id := range current {
+		rr.order = append(rr.order, id)
+		rr.known[id] = true
+	}
+	rand.Shuffle(len(rr.order), func(i, j int) { rr.order[i], rr.order[j] = rr.order[j], rr.order[i] })
+}
+
+// somewhere in the remainder of this pass
+func (rr *RoundRobin) insert(id string) {
+
+	i := rr.pos + random_n(len(rr.order)-rr.pos+1)
+
+	rr.order = append(rr.order, "")
+	copy(rr.order[i+1:], rr.order[i:])
+	rr.order[i] = id
+	rr.known[id] = true
+}
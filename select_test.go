@@ -0,0 +1,139 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 19:55 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func tView(n int) *SelectView {
+
+	v := &SelectView{NumSeeds: 1}
+
+	for i := 0; i < n; i++ {
+		v.Kibitzers = append(v.Kibitzers, &Export{
+			Id:       fmt.Sprintf("peer%d", i),
+			Status:   STATUS_UP,
+			IsSameDC: i%2 == 0,
+			LastTry:  time.Now(),
+		})
+	}
+	return v
+}
+
+func TestRandomSelector(t *testing.T) {
+
+	v := tView(10)
+	v.Skeptical = []*Export{{Id: "new", Status: STATUS_SCEPTICAL}}
+
+	seen := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		seen[RandomSelector{}.Select(v)]++
+	}
+
+	// mostly sceptical, sometimes seed
+	if seen["new"] < 80 || seen["new"]+seen[""] != 100 {
+		t.Fatalf("selected %v", seen)
+	}
+}
+
+func TestWeightedSelector(t *testing.T) {
+
+	v := tView(10)
+	w := &WeightedSelector{SameDC: 1}
+
+	for i := 0; i < 100; i++ {
+		id := w.Select(v)
+		var n int
+		fmt.Sscanf(id, "peer%d", &n)
+		if n%2 != 0 {
+			t.Fatalf("selected remote %s", id)
+		}
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+
+	v := tView(10)
+	v.NumSeeds = 0
+	rr := &RoundRobin{}
+
+	for pass := 0; pass < 3; pass++ {
+		seen := make(map[string]bool)
+		for i := 0; i < 10; i++ {
+			seen[rr.Select(v)] = true
+		}
+		if len(seen) != 10 {
+			t.Fatalf("pass %d: only saw %d", pass, len(seen))
+		}
+	}
+
+	// new peers are probed during the current pass
+	rr.Select(v)
+	rr.Select(v)
+	v.Kibitzers = append(v.Kibitzers, &Export{Id: "late", Status: STATUS_UP})
+	seen := make(map[string]bool)
+	for i := 0; i < 9; i++ {
+		seen[rr.Select(v)] = true
+	}
+	if !seen["late"] || len(seen) != 9 {
+		t.Fatalf("saw %v", seen)
+	}
+
+	// removed peers are skipped
+	v.Kibitzers = v.Kibitzers[:5]
+	seen = make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		seen[rr.Select(v)] = true
+	}
+	if len(seen) != 5 || seen["peer7"] {
+		t.Fatalf("saw %v", seen)
+	}
+
+	// with seeds and sceptical peers, everyone is still probed within the bound
+	v = tView(10)
+	v.Skeptical = []*Export{{Id: "new", Status: STATUS_SCEPTICAL}}
+	rr = &RoundRobin{}
+	bound := rrBound(10, 1, 1)
+
+	last := make(map[string]int)
+	for _, pe := range v.Kibitzers {
+		last[pe.Id] = -1
+	}
+	nseed := 0
+	prev := ""
+
+	for i := 0; i < 10*bound; i++ {
+		id := rr.Select(v)
+
+		switch id {
+		case "":
+			nseed++
+		case "new":
+			if prev == "new" {
+				t.Fatalf("sceptical not interleaved")
+			}
+		default:
+			if i-last[id] > bound {
+				t.Fatalf("%s not probed for %d rounds", id, i-last[id])
+			}
+			last[id] = i
+		}
+		prev = id
+	}
+
+	for id, i := range last {
+		if 10*bound-i > bound {
+			t.Fatalf("%s not probed for %d rounds", id, 10*bound-i)
+		}
+	}
+	// once a pass. (a pass is 11 rounds, + 11 sceptical)
+	if nseed < 10*bound/22-1 {
+		t.Fatalf("seeds used %d times", nseed)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 21:50 (EDT)
+// Function: remember peers we removed, so stale gossip does not bring them back
+
+package kibitz
+
+import (
+	"expvar"
+	"sort"
+	"time"
+)
+
+const TOMBSTONE = 30 * time.Minute // longer than KEEPLOST
+
+type TombReason int
+
+const (
+	TOMB_EXPIRED TombReason = 1 // not heard from in too long
+	TOMB_EVICTED TombReason = 2 // removed by an operator
+	TOMB_LEFT    TombReason = 3 // said it was leaving
+	TOMB_BANNED  TombReason = 4 // banned by an operator
+)
+
+type Tombstone struct {
+	Id          string
+	TimeCreated uint64 // reject records not newer than this
+	Reason      TombReason
+	Expires     time.Time
+	info        *PeerInfo // eviction record, passed along to everyone
+}
+
+var tombrejects = expvar.NewInt("kibitz_tombstone_rejects")
+
+// caller must hold lock
+func (pdb *DB) bury(p *Peer, reason TombReason) {
+
+	dl.Debug("tombstone %s %s", p.id, reason)
+
+	t := &Tombstone{
+		Id:          p.id,
+		TimeCreated: p.info.GetTimeCreated(),
+		Reason:      reason,
+		Expires:     time.Now().Add(pdb.tombTime),
+	}
+
+	if reason == TOMB_EVICTED {
+		// keep spreading the news
+		t.info = p.info
+	}
+
+	pdb.tombs[p.id] = t
+}
+
+// transports that implement this wrap records that are not from a peer itself
+// (eviction notices) differently. otherwise Myself is used
+type wrapper interface {
+	Wrap(*PeerInfo) PeerImport
+}
+
+// the eviction record, for gossiping, as the transport's own type
+func (pdb *DB) evictionData(t *Tombstone) PeerImport {
+
+	if t.info == nil {
+		return nil
+	}
+
+	pi := *t.info
+
+	if w, ok := pdb.iface.(wrapper); ok {
+		return w.Wrap(&pi)
+	}
+	return pdb.iface.Myself(&pi)
+}
+
+// should a record created at created be refused?
+func (t *Tombstone) rejects(created uint64, now time.Time) bool {
+
+	if t.Reason == TOMB_EVICTED && now.Before(t.Expires) {
+		// stays out until the tombstone expires, even if it is still running
+		return true
+	}
+
+	return created <= t.TimeCreated
+}
+
+// is this an old record about a removed peer?
+// caller must hold lock
+func (pdb *DB) buried(pi *PeerInfo) bool {
+
+	t := pdb.tombs[pi.GetServerId()]
+	if t == nil {
+		return false
+	}
+
+	if t.rejects(pi.GetTimeCreated(), time.Now()) {
+		dl.Debug("not ok - tombstone - %s", pi.GetServerId())
+		tombrejects.Add(1)
+		return true
+	}
+
+	// it's back
+	delete(pdb.tombs, t.Id)
+	return false
+}
+
+// caller must hold lock
+func (pdb *DB) cleanupTombs() {
+
+	now := time.Now()
+
+	for id, t := range pdb.tombs {
+		if t.Expires.Before(now) {
+			delete(pdb.tombs, id)
+		}
+	}
+}
+
+// Tombstones returns the recently removed peers
+func (pdb *DB) Tombstones() []*Tombstone {
+
+	pdb.lock.RLock()
+	defer pdb.lock.RUnlock()
+
+	var res []*Tombstone
+	for _, t := range pdb.tombs {
+		tc := *t
+		tc.info = nil
+		res = append(res, &tc)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Id < res[j].Id })
+	return res
+}
+
+func (r TombReason) String() string {
+	switch r {
+	case TOMB_EXPIRED:
+		return "expired"
+	case TOMB_EVICTED:
+		return "evicted"
+	case TOMB_LEFT:
+		return "left"
+	case TOMB_BANNED:
+		return "banned"
+	}
+
+	return "unknown"
+}
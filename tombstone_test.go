@@ -0,0 +1,58 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 22:10 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTombstone(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+
+	// sceptical, then unreachable
+	first := tRecord(b, "mrtesty@ghost", STATUS_UP)
+	t0 := first.TimeCreated
+	b.UpdateSceptical(&tPeer{first})
+	b.PeerDn("mrtesty@ghost")
+
+	ts := b.Tombstones()
+	if len(ts) != 1 || ts[0].Reason != TOMB_EXPIRED || ts[0].TimeCreated != t0 {
+		t.Fatalf("tombstones %+v", ts)
+	}
+
+	// stale gossip does not bring it back
+	stale := tRecord(b, "mrtesty@ghost", STATUS_UP)
+	stale.TimeCreated = t0
+	b.Update(&tPeer{stale})
+	if b.Get("mrtesty@ghost") != nil {
+		t.Fatalf("resurrected")
+	}
+
+	// a newer record does
+	b.Update(&tPeer{tRecord(b, "mrtesty@ghost", STATUS_UP)})
+	if b.Get("mrtesty@ghost") == nil || len(b.Tombstones()) != 0 {
+		t.Fatalf("not updated")
+	}
+
+	// it leaves
+	b.Update(&tPeer{tRecord(b, "mrtesty@ghost", STATUS_DEAD)})
+	ts = b.Tombstones()
+	if b.Get("mrtesty@ghost") != nil || len(ts) != 1 || ts[0].Reason != TOMB_LEFT {
+		t.Fatalf("tombstones %+v", ts)
+	}
+
+	// and is eventually forgotten
+	b.lock.Lock()
+	b.tombs["mrtesty@ghost"].Expires = time.Now().Add(-time.Second)
+	b.lock.Unlock()
+	b.Cleanup()
+
+	if len(b.Tombstones()) != 0 {
+		t.Fatalf("tombstone not expired")
+	}
+}
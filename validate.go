@@ -0,0 +1,157 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 10:05 (EDT)
+// Function: reject malformed records before they get into the table
+
+package kibitz
+
+import (
+	"errors"
+	"expvar"
+	"net"
+	"strconv"
+
+	"github.com/jaw0/kibitz/lamport"
+)
+
+const MAXSKEW = 5 * lamport.Minute // how far in the future may a record be?
+
+var (
+	ErrNilInfo    = errors.New("missing peer info")
+	ErrNoId       = errors.New("missing server id")
+	ErrBadAddr    = errors.New("invalid address")
+	ErrBadStatus  = errors.New("invalid status")
+	ErrFutureTime = errors.New("time is in the future")
+)
+
+var invalidReasons = map[error]string{
+	ErrNilInfo:    "nil",
+	ErrNoId:       "id",
+	ErrBadAddr:    "addr",
+	ErrBadStatus:  "status",
+	ErrFutureTime: "time",
+}
+
+var invalids = expvar.NewMap("kibitz_invalid")
+
+type InvalidError struct {
+	Id     string
+	Err    error // one of the above
+	Detail string
+}
+
+func (e *InvalidError) Error() string {
+
+	msg := "invalid record"
+	if e.Id != "" {
+		msg += " for " + e.Id
+	}
+	msg += ": " + e.Err.Error()
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+func (e *InvalidError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks a received record
+func (pdb *DB) Validate(px PeerImport) error {
+
+	var pi *PeerInfo
+	if px != nil {
+		pi = px.GetPeerInfo()
+	}
+	if pi == nil {
+		return &InvalidError{Err: ErrNilInfo}
+	}
+
+	id := pi.GetServerId()
+	if id == "" {
+		return &InvalidError{Err: ErrNoId}
+	}
+
+	switch PeerStatus(pi.GetStatusCode()) {
+	case STATUS_UP, STATUS_MAYBEDN, STATUS_DOWN, STATUS_DEAD:
+	default:
+		return &InvalidError{Id: id, Err: ErrBadStatus, Detail: strconv.Itoa(int(pi.GetStatusCode()))}
+	}
+
+	for _, ni := range pi.GetNetInfo() {
+		if !validAddr(ni.GetAddr()) {
+			return &InvalidError{Id: id, Err: ErrBadAddr, Detail: ni.GetAddr()}
+		}
+	}
+
+	limit := pdb.clock.Now().Uint64() + MAXSKEW
+
+	for _, t := range []uint64{pi.GetTimeCreated(), pi.GetTimeChecked(), pi.GetTimeLastUp(), pi.GetTimeConf(), pi.GetTimeUpSince()} {
+		if t > limit {
+			return &InvalidError{Id: id, Err: ErrFutureTime}
+		}
+	}
+
+	return nil
+}
+
+func (pdb *DB) invalid(err error, src string) {
+
+	if src == "" {
+		src = "unknown"
+	}
+	dl.Verbose("rejected update from %s: %v", src, err)
+
+	reason := "other"
+	var ierr *InvalidError
+	if errors.As(err, &ierr) {
+		if r, ok := invalidReasons[ierr.Err]; ok {
+			reason = r
+		}
+	}
+
+	invalids.Add(reason, 1)
+}
+
+// host:port, host is an ip address or a dns name
+func validAddr(addr string) bool {
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return false
+	}
+
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	return validHostname(host)
+}
+
+func validHostname(host string) bool {
+
+	if host == "" || len(host) > 253 {
+		return false
+	}
+
+	alpha := false
+
+	for i := 0; i < len(host); i++ {
+		c := host[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			alpha = true
+		case c >= '0' && c <= '9', c == '-', c == '.':
+		default:
+			return false
+		}
+	}
+
+	// all digits + dots is a bogus ip, not a name
+	return alpha
+}
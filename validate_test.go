@@ -0,0 +1,58 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 10:30 (EDT)
+// Function:
+
+package kibitz
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+
+	b := tNewDB("u13-r14.phlccs1.example.com", 1234)
+	now := b.ClockNow()
+
+	good := func() *PeerInfo {
+		return tRecord(b, "mrtesty@valid", STATUS_UP, "10.1.2.3:1234", "[fd00::1]:1234", "kibitz.example.com:80")
+	}
+
+	if err := b.Validate(&tPeer{good()}); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	cases := []struct {
+		fix func(*PeerInfo) *PeerInfo
+		err error
+	}{
+		{func(pi *PeerInfo) *PeerInfo { return nil }, ErrNilInfo},
+		{func(pi *PeerInfo) *PeerInfo { pi.ServerId = ""; return pi }, ErrNoId},
+		{func(pi *PeerInfo) *PeerInfo { pi.StatusCode = 99; return pi }, ErrBadStatus},
+		{func(pi *PeerInfo) *PeerInfo { pi.StatusCode = int32(STATUS_SCEPTICAL); return pi }, ErrBadStatus},
+		{func(pi *PeerInfo) *PeerInfo { pi.NetInfo[0].Addr = "10.1.2.3"; return pi }, ErrBadAddr},
+		{func(pi *PeerInfo) *PeerInfo { pi.NetInfo[0].Addr = "10.1.2.300:1234"; return pi }, ErrBadAddr},
+		{func(pi *PeerInfo) *PeerInfo { pi.NetInfo[0].Addr = "10.1.2.3:99999"; return pi }, ErrBadAddr},
+		{func(pi *PeerInfo) *PeerInfo { pi.TimeCreated = now + 60*MAXSKEW; return pi }, ErrFutureTime},
+	}
+
+	for i, c := range cases {
+		err := b.Validate(&tPeer{c.fix(good())})
+		if !errors.Is(err, c.err) {
+			t.Errorf("case %d: expected %v, got %v", i, c.err, err)
+		}
+	}
+
+	// does not panic, does not get in
+	b.Update(&tPeer{nil})
+	b.UpdateSceptical(&tPeer{nil})
+
+	bad := good()
+	bad.StatusCode = 99
+	b.Update(&tPeer{bad})
+
+	if b.Get("mrtesty@valid") != nil {
+		t.Fatalf("invalid record accepted")
+	}
+}